@@ -25,6 +25,7 @@ import (
 	"github.com/omega-cyber/mesh-network/internal/config"
 	"github.com/omega-cyber/mesh-network/internal/grpc"
 	"github.com/omega-cyber/mesh-network/internal/p2p"
+	"github.com/omega-cyber/mesh-network/internal/trustfabric"
 	"github.com/sirupsen/logrus"
 )
 
@@ -36,16 +37,25 @@ func main() {
 	logrus.SetLevel(logrus.InfoLevel)
 
 	cfg := config.Load()
+	if err := cfg.Validate(); err != nil {
+		logrus.WithError(err).Fatal("Invalid configuration")
+	}
 	logrus.Infof("Starting Mesh Node %s with gRPC Port %d, P2P Port %d", cfg.Node.ID, cfg.Node.GRPCPort, cfg.Node.P2PPort)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	// Initialize Trust Fabric Client (placeholder)
-	// tfClient, err := grpc.NewTrustFabricClient(cfg.TrustFabric.GRPCAddress)
-	// if err != nil {
-	// 	logrus.WithError(err).Fatal("Failed to create Trust Fabric gRPC client")
-	// }
+	// Connect to the Trust Fabric service, retrying with exponential backoff
+	// instead of failing outright on a transient outage.
+	tfCreds, err := trustfabric.ClientCredentialsOption(cfg.Security)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to build Trust Fabric client credentials")
+	}
+	tfConn, err := trustfabric.NewDialer(cfg.TrustFabric).Dial(ctx, tfCreds)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to connect to Trust Fabric")
+	}
+	defer tfConn.Close()
 
 	// Create and start the P2P node
 	p2pNode, err := p2p.NewNode(ctx, &cfg.Node)
@@ -60,8 +70,14 @@ func main() {
 	}
 
 	// Create and start the gRPC server (it sets its own stream handler on the libp2p host)
-	grpcServer := grpc.NewServer(p2pNode)
+	grpcServer, err := grpc.NewServer(p2pNode, cfg)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to create gRPC server")
+	}
 	grpcServer.Serve() // This call is non-blocking and sets up stream handlers.
+	if _, err := grpcServer.ServeTCP(fmt.Sprintf(":%d", cfg.Node.GRPCPort)); err != nil {
+		logrus.WithError(err).Fatal("Failed to start gRPC TCP listener")
+	}
 
 	// Graceful shutdown
 	sigChan := make(chan os.Signal, 1)
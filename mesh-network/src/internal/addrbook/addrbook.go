@@ -0,0 +1,364 @@
+// Copyright 2025 c0r3br34k3r
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package addrbook tracks peer addresses learned beyond the static bootstrap
+// list (JoinAddresses), modeled on Tendermint's address book: addresses are
+// split across "new" (unverified) and "old" (dialed successfully at least
+// once) bucket sets, with deterministic bucket assignment by (group, source)
+// so a single reporting source can't flood a bucket with junk entries.
+package addrbook
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/multiformats/go-multiaddr"
+	manet "github.com/multiformats/go-multiaddr/net"
+)
+
+const (
+	// newBucketCount and oldBucketCount are the number of buckets in each
+	// set; more buckets means a flooding source can poison fewer addresses
+	// per bucket.
+	newBucketCount = 64
+	oldBucketCount = 16
+
+	// maxBucketSize caps how many addresses live in any one bucket; once
+	// full, Add evicts a random existing entry to make room.
+	maxBucketSize = 32
+
+	// minBackoff and maxBackoff bound the exponential backoff applied to an
+	// address's next allowed attempt after a failed dial.
+	minBackoff = 1 * time.Second
+	maxBackoff = 1 * time.Hour
+)
+
+// Addr is a single known peer address, keyed by its canonical multiaddr
+// string (including the /p2p/<id> suffix).
+type Addr struct {
+	Multiaddr string
+	// Src identifies the peer that reported this address to us (the PEX
+	// handler passes its PeerID), or is "" if it was seeded from our own
+	// JoinAddresses config.
+	Src string
+
+	Attempts    int
+	LastAttempt time.Time
+	LastSuccess time.Time
+}
+
+// knownAddress is the persisted/in-memory record for one Addr, annotated
+// with which bucket set and index it currently lives in.
+type knownAddress struct {
+	Addr
+
+	Old    bool
+	Bucket int
+}
+
+// AddressBook manages known peer addresses beyond the static bootstrap list,
+// persisting them to a JSON file so a restarted node doesn't have to
+// rediscover its neighborhood from scratch.
+type AddressBook struct {
+	mu     sync.Mutex
+	path   string
+	strict bool
+	rand   *rand.Rand
+
+	addrs      map[string]*knownAddress
+	newBuckets [newBucketCount]map[string]struct{}
+	oldBuckets [oldBucketCount]map[string]struct{}
+}
+
+// New creates an empty AddressBook that persists to path (Save/Load). When
+// strict is true, Add rejects addresses that don't resolve to a routable
+// (non-loopback, non-unspecified) network address.
+func New(path string, strict bool) *AddressBook {
+	b := &AddressBook{
+		path:   path,
+		strict: strict,
+		rand:   rand.New(rand.NewSource(time.Now().UnixNano())),
+		addrs:  make(map[string]*knownAddress),
+	}
+	for i := range b.newBuckets {
+		b.newBuckets[i] = make(map[string]struct{})
+	}
+	for i := range b.oldBuckets {
+		b.oldBuckets[i] = make(map[string]struct{})
+	}
+	return b
+}
+
+// Seed adds a batch of addresses from our own bootstrap config, with Src
+// left empty to mark them as self-reported rather than peer-gossiped.
+func (b *AddressBook) Seed(addrs []string) {
+	for _, a := range addrs {
+		_ = b.Add(a, "")
+	}
+}
+
+// Add records addr as known, reported by src (an identifier such as the
+// PeerID of the peer that told us about it, or "" if self-reported). If addr is already
+// known, Add is a no-op. New entries start in the "new" bucket set.
+func (b *AddressBook) Add(addr, src string) error {
+	maddr, err := multiaddr.NewMultiaddr(addr)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %w", addr, err)
+	}
+	if b.strict && (manet.IsIPLoopback(maddr) || manet.IsIPUnspecified(maddr)) {
+		return fmt.Errorf("address %q is not routable", addr)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.addrs[addr]; ok {
+		return nil
+	}
+
+	idx := bucketIndex(groupKey(addr), src, newBucketCount)
+	b.evictIfFull(b.newBuckets[idx], maxBucketSize)
+
+	ka := &knownAddress{Addr: Addr{Multiaddr: addr, Src: src}, Old: false, Bucket: idx}
+	b.addrs[addr] = ka
+	b.newBuckets[idx][addr] = struct{}{}
+	return nil
+}
+
+// MarkGood promotes addr into the "old" (known-good) bucket set and resets
+// its attempt counter, recording the current time as its last success.
+func (b *AddressBook) MarkGood(addr string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ka, ok := b.addrs[addr]
+	if !ok {
+		return
+	}
+
+	if !ka.Old {
+		delete(b.newBuckets[ka.Bucket], addr)
+		idx := bucketIndex(groupKey(addr), ka.Src, oldBucketCount)
+		b.evictIfFull(b.oldBuckets[idx], maxBucketSize)
+		b.oldBuckets[idx][addr] = struct{}{}
+		ka.Old = true
+		ka.Bucket = idx
+	}
+
+	ka.Attempts = 0
+	ka.LastSuccess = now()
+}
+
+// MarkAttempt records a dial attempt (successful or not) against addr,
+// advancing CanAttempt's backoff window.
+func (b *AddressBook) MarkAttempt(addr string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ka, ok := b.addrs[addr]
+	if !ok {
+		return
+	}
+	ka.Attempts++
+	ka.LastAttempt = now()
+}
+
+// CanAttempt reports whether enough time has passed since addr's last
+// attempt, given its exponential backoff (doubling per attempt, capped at
+// maxBackoff). Unknown addresses are always attemptable.
+func (b *AddressBook) CanAttempt(addr string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ka, ok := b.addrs[addr]
+	if !ok || ka.Attempts == 0 {
+		return true
+	}
+	return now().Sub(ka.LastAttempt) >= backoff(ka.Attempts)
+}
+
+// PickAddress returns a random attemptable address, biased towards the
+// "old" bucket set as bias approaches 1 and the "new" set as it approaches
+// 0. It returns false if no attemptable address is known.
+func (b *AddressBook) PickAddress(bias float64) (string, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	preferOld := b.rand.Float64() < bias
+	if addr, ok := b.pickFrom(preferOld); ok {
+		return addr, true
+	}
+	// Fall back to the other set if the preferred one had nothing attemptable.
+	return b.pickFrom(!preferOld)
+}
+
+func (b *AddressBook) pickFrom(old bool) (string, bool) {
+	var candidates []string
+	for addr, ka := range b.addrs {
+		if ka.Old != old {
+			continue
+		}
+		if ka.Attempts == 0 || now().Sub(ka.LastAttempt) >= backoff(ka.Attempts) {
+			candidates = append(candidates, addr)
+		}
+	}
+	if len(candidates) == 0 {
+		return "", false
+	}
+	return candidates[b.rand.Intn(len(candidates))], true
+}
+
+// Sample returns up to n known addresses (new and old), for gossiping a
+// subset of the book to a peer via a PEX-style exchange RPC.
+func (b *AddressBook) Sample(n int) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	all := make([]string, 0, len(b.addrs))
+	for addr := range b.addrs {
+		all = append(all, addr)
+	}
+	b.rand.Shuffle(len(all), func(i, j int) { all[i], all[j] = all[j], all[i] })
+	if n > len(all) {
+		n = len(all)
+	}
+	return all[:n]
+}
+
+func (b *AddressBook) evictIfFull(bucket map[string]struct{}, max int) {
+	if len(bucket) < max {
+		return
+	}
+	for addr := range bucket {
+		delete(bucket, addr)
+		delete(b.addrs, addr)
+		break
+	}
+}
+
+// persistedAddr is the on-disk JSON shape for Save/Load.
+type persistedAddr struct {
+	Addr
+	Old bool `json:"old"`
+}
+
+// Save persists the address book to its configured path as JSON.
+func (b *AddressBook) Save() error {
+	b.mu.Lock()
+	out := make([]persistedAddr, 0, len(b.addrs))
+	for _, ka := range b.addrs {
+		out = append(out, persistedAddr{Addr: ka.Addr, Old: ka.Old})
+	}
+	b.mu.Unlock()
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal address book: %w", err)
+	}
+	if err := os.WriteFile(b.path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write address book to %s: %w", b.path, err)
+	}
+	return nil
+}
+
+// Load reads the address book back from its configured path, re-deriving
+// bucket assignment for each address. A missing file is not an error; the
+// book simply starts empty.
+func (b *AddressBook) Load() error {
+	data, err := os.ReadFile(b.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read address book from %s: %w", b.path, err)
+	}
+
+	var in []persistedAddr
+	if err := json.Unmarshal(data, &in); err != nil {
+		return fmt.Errorf("failed to unmarshal address book: %w", err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, pa := range in {
+		idx := bucketIndex(groupKey(pa.Multiaddr), pa.Src, bucketCountFor(pa.Old))
+		ka := &knownAddress{Addr: pa.Addr, Old: pa.Old, Bucket: idx}
+		b.addrs[pa.Multiaddr] = ka
+		if pa.Old {
+			b.oldBuckets[idx][pa.Multiaddr] = struct{}{}
+		} else {
+			b.newBuckets[idx][pa.Multiaddr] = struct{}{}
+		}
+	}
+	return nil
+}
+
+// backoff returns the minimum delay required since the last attempt before
+// another is allowed, doubling per attempt and capped at maxBackoff.
+func backoff(attempts int) time.Duration {
+	d := minBackoff
+	for i := 0; i < attempts-1 && d < maxBackoff; i++ {
+		d *= 2
+	}
+	if d > maxBackoff {
+		d = maxBackoff
+	}
+	return d
+}
+
+func bucketCountFor(old bool) int {
+	if old {
+		return oldBucketCount
+	}
+	return newBucketCount
+}
+
+// bucketIndex deterministically maps (group, src) to a bucket in [0, numBuckets),
+// so a single source can only ever land its addresses in a small, predictable
+// set of buckets rather than flooding the whole book.
+func bucketIndex(group, src string, numBuckets int) int {
+	h := sha256.Sum256([]byte(group + "|" + src))
+	return int(binary.BigEndian.Uint64(h[:8]) % uint64(numBuckets))
+}
+
+// groupKey derives a coarse network "group" for addr (its IPv4/IPv6 host, if
+// any) so bucket assignment resists a single subnet flooding many buckets
+// under different source peers. Addresses that aren't IP-based multiaddrs
+// just use the full string.
+func groupKey(addr string) string {
+	maddr, err := multiaddr.NewMultiaddr(addr)
+	if err != nil {
+		return addr
+	}
+	if ip4, err := maddr.ValueForProtocol(multiaddr.P_IP4); err == nil {
+		return ip4
+	}
+	if ip6, err := maddr.ValueForProtocol(multiaddr.P_IP6); err == nil {
+		return ip6
+	}
+	return addr
+}
+
+// now is a seam so tests could fake the clock if ever needed; today it just
+// wraps time.Now.
+func now() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,149 @@
+// Copyright 2025 c0r3br34k3r
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addrbook_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/omega-cyber/mesh-network/internal/addrbook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPeerIDPool holds real base58-encoded peer IDs (derived from freshly
+// generated Ed25519 keys) for use in test multiaddrs: multiaddr.NewMultiaddr
+// rejects a "/p2p/..." component that isn't a valid multihash, so a
+// placeholder like "QmPeer1" fails before any bucket logic even runs.
+var testPeerIDPool = generateTestPeerIDs(256)
+
+func generateTestPeerIDs(n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		_, pub, err := crypto.GenerateKeyPair(crypto.Ed25519, -1)
+		if err != nil {
+			panic(err)
+		}
+		id, err := peer.IDFromPublicKey(pub)
+		if err != nil {
+			panic(err)
+		}
+		ids[i] = id.Pretty()
+	}
+	return ids
+}
+
+func testPeerID(i int) string {
+	return testPeerIDPool[i%len(testPeerIDPool)]
+}
+
+func testAddr(i int) string {
+	return fmt.Sprintf("/ip4/10.0.%d.%d/tcp/4001/p2p/%s", i/256, i%256, testPeerID(i))
+}
+
+func TestAddAndPickAddress(t *testing.T) {
+	book := addrbook.New("", false)
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, book.Add(testAddr(i), "QmSource"))
+	}
+
+	addr, ok := book.PickAddress(0)
+	assert.True(t, ok)
+	assert.NotEmpty(t, addr)
+}
+
+func TestMarkGoodMovesToOldBucket(t *testing.T) {
+	book := addrbook.New("", false)
+	addr := testAddr(1)
+	require.NoError(t, book.Add(addr, "QmSource"))
+
+	book.MarkGood(addr)
+
+	// Biasing entirely towards "old" should now surface this address.
+	picked, ok := book.PickAddress(1)
+	assert.True(t, ok)
+	assert.Equal(t, addr, picked)
+}
+
+func TestMarkAttemptAppliesBackoff(t *testing.T) {
+	book := addrbook.New("", false)
+	addr := testAddr(2)
+	require.NoError(t, book.Add(addr, "QmSource"))
+
+	assert.True(t, book.CanAttempt(addr))
+	book.MarkAttempt(addr)
+	assert.False(t, book.CanAttempt(addr), "address should be backed off immediately after a fresh attempt")
+}
+
+func TestBucketOverflowEvicts(t *testing.T) {
+	book := addrbook.New("", false)
+
+	// All from the same (group, src) pair so they land in the same bucket;
+	// adding more than the bucket's capacity must evict rather than grow
+	// unbounded.
+	const total = 64
+	for i := 0; i < total; i++ {
+		require.NoError(t, book.Add(fmt.Sprintf("/ip4/10.1.1.5/tcp/%d/p2p/%s", 4000+i, testPeerID(i)), "QmFloodSource"))
+	}
+
+	sampled := book.Sample(total)
+	assert.Less(t, len(sampled), total, "bucket overflow should have evicted some addresses")
+}
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "addrbook.json")
+
+	book := addrbook.New(path, false)
+	addrA := testAddr(10)
+	addrB := testAddr(11)
+	require.NoError(t, book.Add(addrA, "QmSource"))
+	require.NoError(t, book.Add(addrB, "QmSource"))
+	book.MarkGood(addrA)
+
+	require.NoError(t, book.Save())
+
+	reloaded := addrbook.New(path, false)
+	require.NoError(t, reloaded.Load())
+
+	sampled := reloaded.Sample(10)
+	assert.ElementsMatch(t, []string{addrA, addrB}, sampled)
+
+	picked, ok := reloaded.PickAddress(1)
+	assert.True(t, ok)
+	assert.Equal(t, addrA, picked, "MarkGood before Save should survive the round trip")
+}
+
+func TestConcurrentAddAndPick(t *testing.T) {
+	book := addrbook.New("", false)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			addr := testAddr(i)
+			_ = book.Add(addr, fmt.Sprintf("QmSource%d", i%5))
+			book.MarkAttempt(addr)
+			book.PickAddress(0.5)
+		}(i)
+	}
+	wg.Wait()
+}
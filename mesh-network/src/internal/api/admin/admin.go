@@ -0,0 +1,135 @@
+// Copyright 2025 c0r3br34k3r
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package admin implements the gRPC AdminService, a read-only view of the
+// mesh node's currently connected libp2p peers for operator tooling. It
+// depends on a Provider rather than *p2p.Node directly so the P2P layer can
+// be faked in tests (see node_provider.go for the real adapter).
+package admin
+
+import (
+	"time"
+
+	"github.com/omega-cyber/mesh-network/gen/proto/go/mesh"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Direction indicates which side of a connection dialed the other.
+type Direction int
+
+const (
+	DirectionUnknown Direction = iota
+	DirectionInbound
+	DirectionOutbound
+)
+
+// PeerInfo describes one currently connected peer.
+type PeerInfo struct {
+	ID                string
+	Addrs             []string
+	Direction         Direction
+	ConnectedDuration time.Duration
+	LastSeenLatency   time.Duration
+}
+
+// PeerEvent is emitted by Provider.Subscribe as peers connect and disconnect.
+type PeerEvent struct {
+	Added bool // false means the peer was removed.
+	Peer  PeerInfo
+}
+
+// Provider is the P2P layer's connected-peer state, as the admin service
+// needs it. The real implementation is NewNodeProvider; tests supply a fake.
+type Provider interface {
+	ConnectedPeers() []PeerInfo
+	// Subscribe returns a channel of PeerEvents and an unsubscribe function
+	// the caller must invoke once it's done receiving, so the Provider can
+	// stop delivering to (and forget) the channel. A long-running Provider
+	// otherwise leaks one channel per subscriber that never unsubscribes.
+	Subscribe() (<-chan PeerEvent, func())
+}
+
+// Server implements mesh.AdminServiceServer.
+type Server struct {
+	mesh.UnimplementedAdminServiceServer
+	provider Provider
+}
+
+// NewServer creates an admin Server backed by provider. provider may be nil
+// if the P2P layer isn't available yet (e.g. during early startup);
+// PeerInfoStream reports codes.Unavailable in that case rather than
+// panicking.
+func NewServer(provider Provider) *Server {
+	return &Server{provider: provider}
+}
+
+// PeerInfoStream streams the current snapshot of connected peers, followed
+// by add/remove events as they occur, until the client disconnects.
+func (s *Server) PeerInfoStream(req *mesh.PeerInfoStreamRequest, stream mesh.AdminService_PeerInfoStreamServer) error {
+	if s.provider == nil {
+		return status.Error(codes.Unavailable, "admin: no peer provider configured yet")
+	}
+
+	events, unsubscribe := s.provider.Subscribe()
+	defer unsubscribe()
+
+	for _, p := range s.provider.ConnectedPeers() {
+		if err := stream.Send(peerEventProto(PeerEvent{Added: true, Peer: p})); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case ev, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(peerEventProto(ev)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func peerEventProto(ev PeerEvent) *mesh.PeerInfoEvent {
+	kind := mesh.PeerInfoEvent_REMOVED
+	if ev.Added {
+		kind = mesh.PeerInfoEvent_ADDED
+	}
+	return &mesh.PeerInfoEvent{
+		Kind: kind,
+		Peer: &mesh.PeerRuntimeInfo{
+			Id:                  ev.Peer.ID,
+			Addresses:           ev.Peer.Addrs,
+			Direction:           directionProto(ev.Peer.Direction),
+			ConnectedForSeconds: ev.Peer.ConnectedDuration.Seconds(),
+			LastSeenLatencyMs:   float64(ev.Peer.LastSeenLatency.Milliseconds()),
+		},
+	}
+}
+
+func directionProto(d Direction) mesh.PeerRuntimeInfo_Direction {
+	switch d {
+	case DirectionInbound:
+		return mesh.PeerRuntimeInfo_INBOUND
+	case DirectionOutbound:
+		return mesh.PeerRuntimeInfo_OUTBOUND
+	default:
+		return mesh.PeerRuntimeInfo_UNKNOWN
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 c0r3br34k3r
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/omega-cyber/mesh-network/gen/proto/go/mesh"
+	"github.com/omega-cyber/mesh-network/internal/api/admin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeProvider is a Provider test double that lets the test push PeerEvents
+// directly, standing in for a real *p2p.Node.
+type fakeProvider struct {
+	initial []admin.PeerInfo
+	events  chan admin.PeerEvent
+}
+
+func newFakeProvider(initial ...admin.PeerInfo) *fakeProvider {
+	return &fakeProvider{initial: initial, events: make(chan admin.PeerEvent, 8)}
+}
+
+func (f *fakeProvider) ConnectedPeers() []admin.PeerInfo { return f.initial }
+func (f *fakeProvider) Subscribe() (<-chan admin.PeerEvent, func()) {
+	return f.events, func() {}
+}
+
+func dialAdmin(t *testing.T, provider admin.Provider) (mesh.AdminServiceClient, func()) {
+	t.Helper()
+
+	listener := bufconn.Listen(1024 * 1024)
+	grpcServer := grpc.NewServer()
+	mesh.RegisterAdminServiceServer(grpcServer, admin.NewServer(provider))
+	go grpcServer.Serve(listener)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+
+	return mesh.NewAdminServiceClient(conn), func() {
+		conn.Close()
+		grpcServer.Stop()
+	}
+}
+
+func TestPeerInfoStreamDeliversSnapshotThenEvents(t *testing.T) {
+	provider := newFakeProvider(admin.PeerInfo{ID: "QmExisting"})
+	client, cleanup := dialAdmin(t, provider)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.PeerInfoStream(ctx, &mesh.PeerInfoStreamRequest{})
+	require.NoError(t, err)
+
+	snapshot, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, mesh.PeerInfoEvent_ADDED, snapshot.Kind)
+	assert.Equal(t, "QmExisting", snapshot.Peer.Id)
+
+	provider.events <- admin.PeerEvent{Added: true, Peer: admin.PeerInfo{ID: "QmNew"}}
+	added, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, mesh.PeerInfoEvent_ADDED, added.Kind)
+	assert.Equal(t, "QmNew", added.Peer.Id)
+
+	provider.events <- admin.PeerEvent{Added: false, Peer: admin.PeerInfo{ID: "QmNew"}}
+	removed, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, mesh.PeerInfoEvent_REMOVED, removed.Kind)
+	assert.Equal(t, "QmNew", removed.Peer.Id)
+}
+
+func TestPeerInfoStreamRejectsNilProvider(t *testing.T) {
+	client, cleanup := dialAdmin(t, nil)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.PeerInfoStream(ctx, &mesh.PeerInfoStreamRequest{})
+	require.NoError(t, err)
+
+	_, err = stream.Recv()
+	require.Error(t, err)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
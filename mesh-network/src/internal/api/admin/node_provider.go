@@ -0,0 +1,118 @@
+// Copyright 2025 c0r3br34k3r
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/omega-cyber/mesh-network/internal/p2p"
+)
+
+// nodeProvider adapts a *p2p.Node's libp2p host into a Provider, so the
+// admin gRPC service can report real connection state without depending on
+// p2p.Node directly (easing tests with a fake Provider).
+type nodeProvider struct {
+	node *p2p.Node
+
+	mu   sync.Mutex
+	subs []chan PeerEvent
+}
+
+// NewNodeProvider returns a Provider backed by node's libp2p host.
+func NewNodeProvider(node *p2p.Node) Provider {
+	p := &nodeProvider{node: node}
+	node.Host.Network().Notify(&providerNotifiee{provider: p})
+	return p
+}
+
+func (p *nodeProvider) ConnectedPeers() []PeerInfo {
+	conns := p.node.Host.Network().Conns()
+	peers := make([]PeerInfo, 0, len(conns))
+	for _, c := range conns {
+		peers = append(peers, peerInfoFromConn(p.node, c))
+	}
+	return peers
+}
+
+func (p *nodeProvider) Subscribe() (<-chan PeerEvent, func()) {
+	ch := make(chan PeerEvent, 16)
+	p.mu.Lock()
+	p.subs = append(p.subs, ch)
+	p.mu.Unlock()
+
+	unsubscribe := func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		for i, c := range p.subs {
+			if c == ch {
+				p.subs = append(p.subs[:i], p.subs[i+1:]...)
+				break
+			}
+		}
+	}
+	return ch, unsubscribe
+}
+
+func (p *nodeProvider) broadcast(ev PeerEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, ch := range p.subs {
+		select {
+		case ch <- ev:
+		default:
+			// A slow subscriber drops events rather than blocking the
+			// libp2p network notifiee callback.
+		}
+	}
+}
+
+func peerInfoFromConn(node *p2p.Node, c network.Conn) PeerInfo {
+	stat := c.Stat()
+	direction := DirectionUnknown
+	switch stat.Direction {
+	case network.DirInbound:
+		direction = DirectionInbound
+	case network.DirOutbound:
+		direction = DirectionOutbound
+	}
+
+	return PeerInfo{
+		ID:                c.RemotePeer().Pretty(),
+		Addrs:             []string{c.RemoteMultiaddr().String()},
+		Direction:         direction,
+		ConnectedDuration: time.Since(stat.Opened),
+		LastSeenLatency:   node.Host.Peerstore().LatencyEWMA(c.RemotePeer()),
+	}
+}
+
+// providerNotifiee implements network.Notifiee, translating libp2p
+// connect/disconnect events into PeerEvents for nodeProvider's subscribers.
+type providerNotifiee struct {
+	provider *nodeProvider
+}
+
+func (n *providerNotifiee) Listen(network.Network, multiaddr.Multiaddr)      {}
+func (n *providerNotifiee) ListenClose(network.Network, multiaddr.Multiaddr) {}
+
+func (n *providerNotifiee) Connected(_ network.Network, c network.Conn) {
+	n.provider.broadcast(PeerEvent{Added: true, Peer: peerInfoFromConn(n.provider.node, c)})
+}
+
+func (n *providerNotifiee) Disconnected(_ network.Network, c network.Conn) {
+	n.provider.broadcast(PeerEvent{Added: false, Peer: PeerInfo{ID: c.RemotePeer().Pretty()}})
+}
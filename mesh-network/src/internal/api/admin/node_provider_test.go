@@ -0,0 +1,56 @@
+// Copyright 2025 c0r3br34k3r
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/omega-cyber/mesh-network/internal/api/admin"
+	"github.com/omega-cyber/mesh-network/internal/config"
+	"github.com/omega-cyber/mesh-network/internal/p2p"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNodeProviderSubscribeUnsubscribeStopsDelivery verifies that calling the
+// unsubscribe function returned by Subscribe removes the channel from the
+// provider's subscriber list, so a peer connect/disconnect afterwards is not
+// delivered to it. Without this, every PeerInfoStream RPC would leak its
+// subscription channel for the life of the node.
+func TestNodeProviderSubscribeUnsubscribeStopsDelivery(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	node, err := p2p.NewNode(ctx, &config.NodeConfig{ID: "provider-node", P2PPort: 0, GRPCPort: 0})
+	require.NoError(t, err)
+	defer node.Close()
+
+	provider := admin.NewNodeProvider(node)
+
+	events, unsubscribe := provider.Subscribe()
+	unsubscribe()
+
+	peer, err := p2p.NewNode(ctx, &config.NodeConfig{ID: "provider-peer", P2PPort: 0, GRPCPort: 0})
+	require.NoError(t, err)
+	defer peer.Close()
+	require.NoError(t, node.Host.Connect(ctx, peer.Host.Peerstore().PeerInfo(peer.Host.ID())))
+
+	select {
+	case ev := <-events:
+		t.Fatalf("expected no events after unsubscribe, got %#v", ev)
+	case <-time.After(500 * time.Millisecond):
+	}
+}
@@ -15,6 +15,9 @@
 package config
 
 import (
+	"strings"
+	"time"
+
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
@@ -24,40 +27,172 @@ const (
 	DefaultGRPCPort = 50051
 	DefaultP2PPort  = 7946
 	DefaultNodeID   = "mesh-node-01"
+
+	// DefaultTelemetryTopic is the GossipSub topic that validated telemetry
+	// is republished on by the gRPC StreamTelemetry handler.
+	DefaultTelemetryTopic = "omega/telemetry/v1"
+
+	// DefaultConnMgrLowWater and DefaultConnMgrHighWater bound the libp2p
+	// connection manager's trim range when NodeConfig doesn't override them.
+	DefaultConnMgrLowWater  = 256
+	DefaultConnMgrHighWater = 512
+	// DefaultConnMgrGracePeriod exempts freshly opened connections from
+	// trimming for this long.
+	DefaultConnMgrGracePeriod = 20 * time.Second
+
+	// DefaultReConnectBackoffThreshold caps the delay between TrustFabric
+	// reconnect attempts when TrustFabricConfig doesn't override it.
+	DefaultReConnectBackoffThreshold = 1 * time.Hour
+	// DefaultReConnectTotalTimeThreshold is the total retry budget for a
+	// TrustFabric reconnect loop when TrustFabricConfig doesn't override it.
+	DefaultReConnectTotalTimeThreshold = 1 * time.Hour
+	// DefaultConnectionTimeout bounds a single TrustFabric dial attempt.
+	DefaultConnectionTimeout = 3 * time.Second
 )
 
+// PubSubConfig holds the configuration for the GossipSub/BlossomSub mesh overlay.
+type PubSubConfig struct {
+	// Topics is the set of topics this node joins automatically on startup.
+	Topics []string
+	// ScoreThreshold is the peer score below which a peer is gray-listed
+	// (still connected, but its published messages are dropped).
+	ScoreThreshold float64 `mapstructure:"score_threshold"`
+	// GossipThreshold is the peer score below which a peer is excluded from
+	// gossip (IHAVE/IWANT) but mesh delivery is otherwise unaffected.
+	GossipThreshold float64 `mapstructure:"gossip_threshold"`
+	// PublishThreshold is the peer score below which our own messages are
+	// not forwarded through that peer.
+	PublishThreshold float64 `mapstructure:"publish_threshold"`
+	// InvalidMessageDeliveries is the weight applied to a peer's invalid
+	// message delivery counter in its topic score.
+	InvalidMessageDeliveries float64 `mapstructure:"invalid_message_deliveries"`
+	// InvalidMessageDecay is the decay factor applied to the invalid
+	// message delivery counter each decay interval.
+	InvalidMessageDecay float64 `mapstructure:"invalid_message_decay"`
+}
+
+// TrustedPeer pins an expected PeerID to the multiaddr(s) it is allowed to
+// bootstrap from, rejecting any connection that presents a different key.
+type TrustedPeer struct {
+	PeerID    string `mapstructure:"peer_id"`
+	Multiaddr string
+}
+
 // NodeConfig holds the configuration specific to this mesh node.
 type NodeConfig struct {
 	ID            string
-	GRPCPort      int
-	P2PPort       int
-	JoinAddresses []string
+	GRPCPort      int          `mapstructure:"grpc_port"`
+	P2PPort       int          `mapstructure:"p2p_port"`
+	JoinAddresses []string     `mapstructure:"join_addresses"`
+	PubSub        PubSubConfig `mapstructure:"pub_sub"`
+
+	// IdentityPath is where the node's Ed25519 private key is persisted so
+	// its PeerID (and DHT routing identity) survives restarts. If empty, a
+	// fresh identity is generated in memory and never written to disk.
+	IdentityPath string `mapstructure:"identity_path"`
+	// IdentityPassphrase, when set, encrypts the on-disk private key with
+	// scrypt-derived AES-GCM instead of storing it in the clear.
+	IdentityPassphrase string `mapstructure:"identity_passphrase"`
+	// TrustedPeers pins bootstrap peers to their expected PeerID so a
+	// compromised or spoofed address can't silently take over a role.
+	TrustedPeers []TrustedPeer `mapstructure:"trusted_peers"`
+
+	// ConnMgrLowWater and ConnMgrHighWater bound the connection manager's
+	// trim range: once HighWater connections are open it trims back down
+	// towards LowWater, closing the least useful ones first.
+	ConnMgrLowWater  int `mapstructure:"conn_mgr_low_water"`
+	ConnMgrHighWater int `mapstructure:"conn_mgr_high_water"`
+	// ConnMgrGracePeriod is how long a newly opened connection is exempt
+	// from trimming, so short-lived dials to freshly discovered peers
+	// aren't cut before they're useful.
+	ConnMgrGracePeriod time.Duration `mapstructure:"conn_mgr_grace_period"`
+
+	// AddrFilters is a list of CIDR-like multiaddr masks; any listen or
+	// dialable address matching one of these is never advertised or dialed
+	// (e.g. "/ip4/10.0.0.0/ipcidr/8" to exclude an internal network).
+	AddrFilters []string `mapstructure:"addr_filters"`
+	// AnnounceAddrs overrides the multiaddrs the node advertises to peers
+	// and the DHT, regardless of what it's actually listening on (useful
+	// behind NAT/load balancers with a known public address).
+	AnnounceAddrs []string `mapstructure:"announce_addrs"`
+	// NoAnnounceAddrs removes addresses from the advertised set without
+	// otherwise overriding it (e.g. to hide a loopback or VPN interface).
+	NoAnnounceAddrs []string `mapstructure:"no_announce_addrs"`
+
+	// Roles are rendezvous strings this node advertises on the DHT after
+	// bootstrap (e.g. "sensor", "aggregator", "trust-fabric-gateway"), so
+	// other peers can find it with Node.FindPeers without knowing its
+	// PeerID ahead of time.
+	Roles []string
+
+	// AddrBookPath is where the peer address book (beyond the static
+	// JoinAddresses bootstrap list) is persisted as JSON between restarts.
+	// If empty, the address book is kept in memory only.
+	AddrBookPath string `mapstructure:"addr_book_path"`
+	// AddrBookStrict rejects non-routable (loopback/unspecified) addresses
+	// when adding them to the address book, e.g. from a PEX exchange.
+	AddrBookStrict bool `mapstructure:"addr_book_strict"`
+
+	// NATEnabled turns on UPnP/NAT-PMP port mapping for P2PPort, so a node
+	// behind a home router or similar NAT can still accept inbound
+	// connections without manual port forwarding.
+	NATEnabled bool `mapstructure:"nat_enabled"`
 }
 
 // TrustFabricConfig holds the configuration for connecting to the Trust Fabric service.
 type TrustFabricConfig struct {
-	GRPCAddress string
+	GRPCAddress string `mapstructure:"grpc_address"`
+
+	// ReConnectBackoffThreshold caps the delay between successive reconnect
+	// attempts; the delay doubles after each failure up to this ceiling.
+	ReConnectBackoffThreshold time.Duration `mapstructure:"re_connect_backoff_threshold"`
+	// ReConnectTotalTimeThreshold is the total wall-clock budget a Dialer
+	// spends retrying before giving up with ErrReconnectExhausted.
+	ReConnectTotalTimeThreshold time.Duration `mapstructure:"re_connect_total_time_threshold"`
+	// ConnectionTimeout bounds a single dial attempt.
+	ConnectionTimeout time.Duration `mapstructure:"connection_timeout"`
+}
+
+// SecurityConfig controls whether gRPC traffic is additionally secured with
+// TLS (stacked on top of libp2p's own stream security) and where the
+// certificate material for that TLS layer lives on disk.
+type SecurityConfig struct {
+	// TLSEnabled turns on TLS transport credentials for the gRPC server and
+	// the TrustFabric client dialer. When false, the gRPC server keeps using
+	// its libp2p-identity-derived transport credentials unchanged.
+	TLSEnabled bool `mapstructure:"tls_enabled"`
+	// ClientAuthRequired makes the server request and verify a client
+	// certificate against RootCAFile (mutual TLS), rejecting any connection
+	// that doesn't present one signed by a trusted CA.
+	ClientAuthRequired bool `mapstructure:"client_auth_required"`
+	// CertFile and KeyFile are PEM paths for this node's own certificate and
+	// private key, presented during the TLS handshake.
+	CertFile string `mapstructure:"cert_file"`
+	KeyFile  string `mapstructure:"key_file"`
+	// RootCAFile is a PEM bundle of CAs trusted to sign peer certificates:
+	// the server's client-auth verification pool, and the client's server
+	// verification pool.
+	RootCAFile string `mapstructure:"root_ca_file"`
+	// ServerNameOverride overrides the SNI/verification name a client
+	// expects from the server certificate, for use when dialing by an
+	// address that doesn't match the certificate's subject (e.g. a libp2p
+	// PeerID or internal service name instead of a DNS name).
+	ServerNameOverride string `mapstructure:"server_name_override"`
 }
 
 // Config is the top-level configuration structure for the application.
 type Config struct {
 	Node        NodeConfig
 	TrustFabric TrustFabricConfig
+	Security    SecurityConfig
 }
 
-// Load loads configuration from environment variables and a config file.
+// Load loads configuration from environment variables and a config file
+// named "config" (.yaml) found in /etc/omega/, $HOME/.omega, or the current
+// directory.
 func Load() *Config {
-	viper.AutomaticEnv()
-	viper.SetEnvPrefix("OMEGA") // e.g., OMEGA_NODE_ID, OMEGA_TRUSTFABRIC_GRPCADDRESS
-
-	// Set defaults
-	viper.SetDefault("node.id", DefaultNodeID)
-	viper.SetDefault("node.grpc_port", DefaultGRPCPort)
-	viper.SetDefault("node.p2p_port", DefaultP2PPort)
-	viper.SetDefault("node.join_addresses", []string{})
-	viper.SetDefault("trust_fabric.grpc_address", "localhost:50050")
+	setEnvAndDefaults()
 
-	// Try to read config file if it exists (e.g., config.yaml)
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
 	viper.AddConfigPath("/etc/omega/")
@@ -69,10 +204,69 @@ func Load() *Config {
 		}
 	}
 
+	return unmarshal()
+}
+
+// LoadFrom loads configuration from environment variables and the config
+// file at the given path, without touching viper's config-name/search-path
+// lookup. This is what WatchAndReload uses to reload from the exact file it
+// is watching: calling Load (which hardcodes SetConfigName("config")) a
+// second time would silently reset any caller-configured lookup back to
+// that default and reload the wrong file.
+func LoadFrom(path string) *Config {
+	setEnvAndDefaults()
+
+	viper.SetConfigFile(path)
+	if err := viper.ReadInConfig(); err != nil {
+		logrus.WithError(err).Warn("Failed to read config file")
+	}
+
+	return unmarshal()
+}
+
+// setEnvAndDefaults configures viper's environment variable handling and
+// default values; shared by Load and LoadFrom so the two stay in sync.
+func setEnvAndDefaults() {
+	viper.AutomaticEnv()
+	viper.SetEnvPrefix("OMEGA") // e.g., OMEGA_NODE_ID, OMEGA_TRUST_FABRIC_GRPC_ADDRESS
+	// Map nested keys like "node.id" to OMEGA_NODE_ID rather than OMEGA_NODE.ID.
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	// A set-but-empty env var (e.g. OMEGA_NODE_ID=) should fall through to
+	// the config file or default, not clobber them with "" — an operator
+	// clearing an env var in a shell script shouldn't silently blank out a
+	// configured node ID.
+	viper.AllowEmptyEnv(false)
+
+	viper.SetDefault("node.id", DefaultNodeID)
+	viper.SetDefault("node.grpc_port", DefaultGRPCPort)
+	viper.SetDefault("node.p2p_port", DefaultP2PPort)
+	viper.SetDefault("node.join_addresses", []string{})
+	viper.SetDefault("node.pub_sub.topics", []string{DefaultTelemetryTopic})
+	viper.SetDefault("node.pub_sub.score_threshold", -500.0)
+	viper.SetDefault("node.pub_sub.gossip_threshold", -100.0)
+	viper.SetDefault("node.pub_sub.publish_threshold", -250.0)
+	viper.SetDefault("node.pub_sub.invalid_message_deliveries", 1.0)
+	viper.SetDefault("node.pub_sub.invalid_message_decay", 0.5)
+	viper.SetDefault("node.conn_mgr_low_water", DefaultConnMgrLowWater)
+	viper.SetDefault("node.conn_mgr_high_water", DefaultConnMgrHighWater)
+	viper.SetDefault("node.conn_mgr_grace_period", DefaultConnMgrGracePeriod)
+	viper.SetDefault("trust_fabric.grpc_address", "localhost:50050")
+	viper.SetDefault("trust_fabric.re_connect_backoff_threshold", DefaultReConnectBackoffThreshold)
+	viper.SetDefault("trust_fabric.re_connect_total_time_threshold", DefaultReConnectTotalTimeThreshold)
+	viper.SetDefault("trust_fabric.connection_timeout", DefaultConnectionTimeout)
+	viper.SetDefault("security.tls_enabled", false)
+	viper.SetDefault("security.client_auth_required", false)
+	viper.SetDefault("node.addr_book_path", "")
+	viper.SetDefault("node.addr_book_strict", false)
+	viper.SetDefault("node.nat_enabled", false)
+}
+
+// unmarshal decodes viper's current state into a Config, fatally if it
+// can't — called only after setEnvAndDefaults and ReadInConfig.
+func unmarshal() *Config {
 	cfg := &Config{}
 	if err := viper.Unmarshal(cfg); err != nil {
 		logrus.WithError(err).Fatal("Failed to unmarshal config")
 	}
-
 	return cfg
 }
@@ -90,3 +90,37 @@ trust_fabric:
 		assert.Equal(t, "tf.file.com:50050", cfg.TrustFabric.GRPCAddress)
 	})
 }
+
+func TestLoadEnvVarEmptyVsUnsetVsSet(t *testing.T) {
+	cases := []struct {
+		name   string
+		setEnv bool
+		value  string
+		want   string
+	}{
+		{name: "unset falls back to default", setEnv: false, want: config.DefaultNodeID},
+		{name: "empty falls back to default", setEnv: true, value: "", want: config.DefaultNodeID},
+		{name: "set overrides default", setEnv: true, value: "test-node-789", want: "test-node-789"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			viper.Reset()
+			if tc.setEnv {
+				os.Setenv("OMEGA_NODE_ID", tc.value)
+				defer os.Unsetenv("OMEGA_NODE_ID")
+			} else {
+				os.Unsetenv("OMEGA_NODE_ID")
+			}
+
+			cfg := config.Load()
+			assert.Equal(t, tc.want, cfg.Node.ID)
+			// Multi-word fields must also bind correctly: a missing
+			// mapstructure tag silently leaves these at their zero value
+			// regardless of defaults, which TestLoad alone didn't catch
+			// since Node.ID binds by accident (EqualFold("id", "ID")).
+			assert.Equal(t, config.DefaultGRPCPort, cfg.Node.GRPCPort)
+			assert.Equal(t, config.DefaultP2PPort, cfg.Node.P2PPort)
+		})
+	}
+}
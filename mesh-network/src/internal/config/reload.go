@@ -0,0 +1,160 @@
+// Copyright 2025 c0r3br34k3r
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// ChangeEvent is dispatched to WatchAndReload's onChange callback whenever a
+// reload detects a difference in the corresponding part of Config, so
+// subsystems only react to the parts of the config that actually moved.
+type ChangeEvent interface {
+	isChangeEvent()
+}
+
+// NodeChanged is dispatched when anything under Config.Node differs from the
+// previously loaded value, other than JoinAddresses (see JoinAddressesChanged).
+type NodeChanged struct {
+	Old, New NodeConfig
+}
+
+// TrustFabricChanged is dispatched when Config.TrustFabric differs from the
+// previously loaded value.
+type TrustFabricChanged struct {
+	Old, New TrustFabricConfig
+}
+
+// JoinAddressesChanged is dispatched specifically for Config.Node.JoinAddresses,
+// split out from NodeChanged because it drives peer connect/disconnect
+// rather than a subsystem rebind.
+type JoinAddressesChanged struct {
+	Old, New []string
+}
+
+func (NodeChanged) isChangeEvent()          {}
+func (TrustFabricChanged) isChangeEvent()   {}
+func (JoinAddressesChanged) isChangeEvent() {}
+
+// WatchAndReload watches the config file viper last read and invokes
+// onChange with the set of typed events describing what moved whenever the
+// file changes. The fsnotify watch is placed on the config file's own
+// directory rather than its symlink target's, so a Kubernetes ConfigMap
+// mount — where the file is a symlink into a periodically re-symlinked
+// "..data" directory, and reloading is driven by an atomic rename landing in
+// the file's own directory — is handled correctly. WatchAndReload returns
+// only after the watcher goroutine is confirmed running; cancel ctx to stop
+// it.
+//
+// Callers must have already loaded configuration via Load or LoadFrom, so
+// viper.ConfigFileUsed() reports the file to watch; WatchAndReload reloads
+// from that exact path on every change (via LoadFrom) rather than Load,
+// since Load hardcodes its own config-name/search-path lookup and would
+// silently reload a different file than the one being watched.
+func WatchAndReload(ctx context.Context, onChange func([]ChangeEvent)) error {
+	configFile := viper.ConfigFileUsed()
+	if configFile == "" {
+		return fmt.Errorf("config: no config file in use; call Load or LoadFrom before WatchAndReload")
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	// Watch configFile's own directory, not its symlink target's: that
+	// directory is stable across a ConfigMap-style atomic symlink swap
+	// (the swap itself is a rename landing in this directory), whereas the
+	// target directory changes out from under the watch the moment the
+	// swap happens, so a watch on it never sees the very rename it's meant
+	// to catch.
+	if err := watcher.Add(filepath.Dir(configFile)); err != nil {
+		watcher.Close()
+		return err
+	}
+
+	current := *LoadFrom(configFile)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer watcher.Close()
+		wg.Done() // signal that the watcher goroutine is running and the loop below has started.
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != filepath.Clean(configFile) && filepath.Dir(event.Name) != filepath.Dir(configFile) {
+					continue
+				}
+
+				if _, err := filepath.EvalSymlinks(configFile); err != nil {
+					logrus.WithError(err).Warn("config: failed to resolve config file symlink after change event")
+					continue
+				}
+
+				reloadAndDispatch(configFile, &current, onChange)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				logrus.WithError(err).Warn("config: fsnotify watcher error")
+			}
+		}
+	}()
+	wg.Wait()
+
+	return nil
+}
+
+// reloadAndDispatch re-reads the config from path, diffs it against previous
+// (updating it in place), and invokes onChange with whatever differs.
+func reloadAndDispatch(path string, previous *Config, onChange func([]ChangeEvent)) {
+	next := *LoadFrom(path)
+
+	var events []ChangeEvent
+	if !reflect.DeepEqual(previous.Node.JoinAddresses, next.Node.JoinAddresses) {
+		events = append(events, JoinAddressesChanged{Old: previous.Node.JoinAddresses, New: next.Node.JoinAddresses})
+	}
+	if !nodeConfigEqualIgnoringJoinAddresses(previous.Node, next.Node) {
+		events = append(events, NodeChanged{Old: previous.Node, New: next.Node})
+	}
+	if !reflect.DeepEqual(previous.TrustFabric, next.TrustFabric) {
+		events = append(events, TrustFabricChanged{Old: previous.TrustFabric, New: next.TrustFabric})
+	}
+
+	*previous = next
+
+	if len(events) > 0 && onChange != nil {
+		onChange(events)
+	}
+}
+
+func nodeConfigEqualIgnoringJoinAddresses(a, b NodeConfig) bool {
+	a.JoinAddresses, b.JoinAddresses = nil, nil
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,143 @@
+// Copyright 2025 c0r3br34k3r
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/omega-cyber/mesh-network/internal/config"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func baseConfigYAML(nodeID string, grpcPort int) string {
+	return "node:\n" +
+		"  id: \"" + nodeID + "\"\n" +
+		"  grpc_port: " + strconv.Itoa(grpcPort) + "\n"
+}
+
+func loadFromDir(t *testing.T, dir, configFile string) *config.Config {
+	t.Helper()
+	viper.Reset()
+	return config.LoadFrom(filepath.Join(dir, configFile+".yaml"))
+}
+
+func waitForChange(t *testing.T, events <-chan []config.ChangeEvent, timeout time.Duration) []config.ChangeEvent {
+	t.Helper()
+	select {
+	case evs := <-events:
+		return evs
+	case <-time.After(timeout):
+		t.Fatal("timed out waiting for config change event")
+		return nil
+	}
+}
+
+func TestWatchAndReloadErrorsWithoutAConfigFile(t *testing.T) {
+	viper.Reset()
+	err := config.WatchAndReload(context.Background(), func([]config.ChangeEvent) {})
+	require.Error(t, err)
+}
+
+func TestWatchAndReloadOnFileOverwrite(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "watch_config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(baseConfigYAML("node-a", 6000)), 0644))
+
+	loadFromDir(t, dir, "watch_config")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make(chan []config.ChangeEvent, 4)
+	require.NoError(t, config.WatchAndReload(ctx, func(evs []config.ChangeEvent) {
+		events <- evs
+	}))
+
+	require.NoError(t, os.WriteFile(configPath, []byte(baseConfigYAML("node-b", 6001)), 0644))
+
+	evs := waitForChange(t, events, 5*time.Second)
+	assertHasNodeChange(t, evs)
+}
+
+func TestWatchAndReloadOnRenameAtop(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "watch_config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(baseConfigYAML("node-a", 6000)), 0644))
+
+	loadFromDir(t, dir, "watch_config")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make(chan []config.ChangeEvent, 4)
+	require.NoError(t, config.WatchAndReload(ctx, func(evs []config.ChangeEvent) {
+		events <- evs
+	}))
+
+	tmpPath := filepath.Join(dir, "watch_config.yaml.tmp")
+	require.NoError(t, os.WriteFile(tmpPath, []byte(baseConfigYAML("node-c", 6002)), 0644))
+	require.NoError(t, os.Rename(tmpPath, configPath))
+
+	evs := waitForChange(t, events, 5*time.Second)
+	assertHasNodeChange(t, evs)
+}
+
+func TestWatchAndReloadOnSymlinkTargetSwap(t *testing.T) {
+	dir := t.TempDir()
+
+	targetA := filepath.Join(dir, "v1", "watch_config.yaml")
+	targetB := filepath.Join(dir, "v2", "watch_config.yaml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(targetA), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Dir(targetB), 0755))
+	require.NoError(t, os.WriteFile(targetA, []byte(baseConfigYAML("node-a", 6000)), 0644))
+	require.NoError(t, os.WriteFile(targetB, []byte(baseConfigYAML("node-d", 6003)), 0644))
+
+	link := filepath.Join(dir, "watch_config.yaml")
+	require.NoError(t, os.Symlink(targetA, link))
+
+	loadFromDir(t, dir, "watch_config")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make(chan []config.ChangeEvent, 4)
+	require.NoError(t, config.WatchAndReload(ctx, func(evs []config.ChangeEvent) {
+		events <- evs
+	}))
+
+	newLink := link + ".next"
+	require.NoError(t, os.Symlink(targetB, newLink))
+	require.NoError(t, os.Rename(newLink, link))
+
+	evs := waitForChange(t, events, 5*time.Second)
+	assertHasNodeChange(t, evs)
+}
+
+func assertHasNodeChange(t *testing.T, evs []config.ChangeEvent) {
+	t.Helper()
+	for _, ev := range evs {
+		if _, ok := ev.(config.NodeChanged); ok {
+			return
+		}
+	}
+	assert.Fail(t, "expected a NodeChanged event", "got: %#v", evs)
+}
@@ -0,0 +1,86 @@
+// Copyright 2025 c0r3br34k3r
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// BuildServerTLS assembles a *tls.Config for the gRPC server from sec's PEM
+// files. If sec.ClientAuthRequired is set, RootCAFile is loaded as the pool
+// client certificates must chain to and mutual TLS is enforced.
+func BuildServerTLS(sec SecurityConfig) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(sec.CertFile, sec.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load server certificate/key: %w", err)
+	}
+
+	tlsCfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+	}
+
+	if sec.ClientAuthRequired {
+		pool, err := loadCAPool(sec.RootCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client CA pool: %w", err)
+		}
+		tlsCfg.ClientCAs = pool
+		tlsCfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsCfg, nil
+}
+
+// BuildClientTLS assembles a *tls.Config for a gRPC client dialer from sec's
+// PEM files. RootCAFile is loaded to verify the server's certificate. If
+// CertFile/KeyFile are set, the client also presents a certificate, for
+// servers configured with ClientAuthRequired.
+func BuildClientTLS(sec SecurityConfig) (*tls.Config, error) {
+	pool, err := loadCAPool(sec.RootCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load root CA pool: %w", err)
+	}
+
+	tlsCfg := &tls.Config{
+		RootCAs:    pool,
+		ServerName: sec.ServerNameOverride,
+	}
+
+	if sec.CertFile != "" || sec.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(sec.CertFile, sec.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate/key: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsCfg, nil
+}
+
+func loadCAPool(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in CA bundle %s", path)
+	}
+	return pool, nil
+}
@@ -0,0 +1,134 @@
+// Copyright 2025 c0r3br34k3r
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"crypto/tls"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/omega-cyber/mesh-network/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveOnce(t *testing.T, listener net.Listener, tlsCfg *tls.Config) {
+	t.Helper()
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		tlsConn := tls.Server(conn, tlsCfg)
+		defer tlsConn.Close()
+		if err := tlsConn.Handshake(); err != nil {
+			return
+		}
+		io.Copy(tlsConn, tlsConn)
+	}()
+}
+
+func TestBuildServerClientTLS_ServerOnly(t *testing.T) {
+	serverTLS, err := config.BuildServerTLS(config.SecurityConfig{
+		CertFile: "testdata/server-cert.pem",
+		KeyFile:  "testdata/server-key.pem",
+	})
+	require.NoError(t, err)
+	assert.Empty(t, serverTLS.ClientCAs)
+	assert.Equal(t, tls.NoClientCert, serverTLS.ClientAuth)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	serveOnce(t, listener, serverTLS)
+
+	clientTLS, err := config.BuildClientTLS(config.SecurityConfig{
+		RootCAFile:         "testdata/ca-cert.pem",
+		ServerNameOverride: "localhost",
+	})
+	require.NoError(t, err)
+
+	conn, err := tls.Dial("tcp", listener.Addr().String(), clientTLS)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, conn.Handshake())
+}
+
+func TestBuildServerClientTLS_MutualAuth(t *testing.T) {
+	serverTLS, err := config.BuildServerTLS(config.SecurityConfig{
+		CertFile:           "testdata/server-cert.pem",
+		KeyFile:            "testdata/server-key.pem",
+		ClientAuthRequired: true,
+		RootCAFile:         "testdata/ca-cert.pem",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, serverTLS.ClientAuth)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	serveOnce(t, listener, serverTLS)
+
+	clientTLS, err := config.BuildClientTLS(config.SecurityConfig{
+		RootCAFile:         "testdata/ca-cert.pem",
+		CertFile:           "testdata/client-cert.pem",
+		KeyFile:            "testdata/client-key.pem",
+		ServerNameOverride: "localhost",
+	})
+	require.NoError(t, err)
+
+	conn, err := tls.Dial("tcp", listener.Addr().String(), clientTLS)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, conn.Handshake())
+}
+
+func TestBuildServerClientTLS_MutualAuthRejectsMissingClientCert(t *testing.T) {
+	serverTLS, err := config.BuildServerTLS(config.SecurityConfig{
+		CertFile:           "testdata/server-cert.pem",
+		KeyFile:            "testdata/server-key.pem",
+		ClientAuthRequired: true,
+		RootCAFile:         "testdata/ca-cert.pem",
+	})
+	require.NoError(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	serveOnce(t, listener, serverTLS)
+
+	clientTLS, err := config.BuildClientTLS(config.SecurityConfig{
+		RootCAFile:         "testdata/ca-cert.pem",
+		ServerNameOverride: "localhost",
+	})
+	require.NoError(t, err)
+
+	conn, err := tls.Dial("tcp", listener.Addr().String(), clientTLS)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// Under TLS 1.3, Handshake itself can return nil even though the server
+	// is about to reject the connection: the server defers its
+	// certificate_required alert until the client's first post-handshake
+	// Read/Write, rather than failing the handshake outright. So the
+	// rejection has to be observed there, not from Handshake's return value.
+	_ = conn.Handshake()
+	_, err = conn.Write([]byte("hello"))
+	if err == nil {
+		_, err = conn.Read(make([]byte, 1))
+	}
+	assert.Error(t, err, "server must reject a client that presents no certificate")
+}
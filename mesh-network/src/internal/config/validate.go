@@ -0,0 +1,78 @@
+// Copyright 2025 c0r3br34k3r
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/multiformats/go-multiaddr"
+)
+
+// Validate checks Config for problems that would otherwise surface as
+// confusing failures deep in node startup, collecting every problem it
+// finds instead of stopping at the first.
+func (c *Config) Validate() error {
+	var result *multierror.Error
+
+	if c.Node.ID == "" {
+		result = multierror.Append(result, fmt.Errorf("node.id must not be empty"))
+	}
+	if err := validatePort(c.Node.GRPCPort); err != nil {
+		result = multierror.Append(result, fmt.Errorf("node.grpc_port: %w", err))
+	}
+	if err := validatePort(c.Node.P2PPort); err != nil {
+		result = multierror.Append(result, fmt.Errorf("node.p2p_port: %w", err))
+	}
+	for _, addr := range c.Node.JoinAddresses {
+		if _, err := multiaddr.NewMultiaddr(addr); err != nil {
+			result = multierror.Append(result, fmt.Errorf("node.join_addresses: invalid multiaddr %q: %w", addr, err))
+		}
+	}
+	if err := validateHostPort(c.TrustFabric.GRPCAddress); err != nil {
+		result = multierror.Append(result, fmt.Errorf("trust_fabric.grpc_address: %w", err))
+	}
+
+	return result.ErrorOrNil()
+}
+
+// validatePort reports whether port falls in the valid TCP/UDP port range.
+func validatePort(port int) error {
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("port %d is out of range (1-65535)", port)
+	}
+	return nil
+}
+
+// validateHostPort checks that addr is a well-formed host:port pair with a
+// valid port number. It does not perform a DNS lookup: a misconfigured but
+// syntactically valid hostname should fail at dial time with a clear
+// connection error, not at startup with a transient DNS hiccup.
+func validateHostPort(addr string) error {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("%q is not a valid host:port: %w", addr, err)
+	}
+	if host == "" {
+		return fmt.Errorf("%q is missing a host", addr)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return fmt.Errorf("%q has a non-numeric port: %w", addr, err)
+	}
+	return validatePort(port)
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 c0r3br34k3r
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"testing"
+
+	"github.com/omega-cyber/mesh-network/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func validConfig() *config.Config {
+	return &config.Config{
+		Node: config.NodeConfig{
+			ID:            "node-1",
+			GRPCPort:      config.DefaultGRPCPort,
+			P2PPort:       config.DefaultP2PPort,
+			JoinAddresses: []string{"/ip4/127.0.0.1/tcp/4001/p2p/12D3KooWC5xfSz9aARjiDaAd4vwaV7gyTNNmUd4SSpL5HxJ7mkYn"},
+		},
+		TrustFabric: config.TrustFabricConfig{
+			GRPCAddress: "localhost:50050",
+		},
+	}
+}
+
+func TestValidateAcceptsValidConfig(t *testing.T) {
+	assert.NoError(t, validConfig().Validate())
+}
+
+func TestValidateCollectsAllProblems(t *testing.T) {
+	cfg := validConfig()
+	cfg.Node.ID = ""
+	cfg.Node.GRPCPort = 0
+	cfg.Node.P2PPort = 70000
+	cfg.Node.JoinAddresses = []string{"not-a-multiaddr"}
+	cfg.TrustFabric.GRPCAddress = "not-a-host-port"
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+
+	msg := err.Error()
+	assert.Contains(t, msg, "node.id")
+	assert.Contains(t, msg, "node.grpc_port")
+	assert.Contains(t, msg, "node.p2p_port")
+	assert.Contains(t, msg, "join_addresses")
+	assert.Contains(t, msg, "grpc_address")
+}
+
+func TestValidateRejectsOutOfRangePort(t *testing.T) {
+	cfg := validConfig()
+	cfg.Node.P2PPort = 99999
+	assert.Error(t, cfg.Validate())
+}
+
+func TestValidateRejectsMalformedTrustFabricAddress(t *testing.T) {
+	cfg := validConfig()
+	cfg.TrustFabric.GRPCAddress = "missing-port-host"
+	assert.Error(t, cfg.Validate())
+}
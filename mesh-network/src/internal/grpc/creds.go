@@ -0,0 +1,158 @@
+// Copyright 2025 c0r3br34k3r
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/omega-cyber/mesh-network/internal/config"
+	"github.com/omega-cyber/mesh-network/internal/p2p"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+)
+
+// spiffeURIPrefix is the synthetic SPIFFE-like identity namespace used for
+// libp2p peers, so interceptors can reason about the remote identity the
+// same way they would a real SPIFFE SVID.
+const spiffeURIPrefix = "spiffe://omega/peer/"
+
+// lp2pAuthInfo is the credentials.AuthInfo derived from the libp2p peer's
+// Ed25519 identity. It satisfies gRPC-level auth (interceptors, per-RPC
+// creds) on top of the transport security libp2p already provides.
+type lp2pAuthInfo struct {
+	peerID peer.ID
+}
+
+// AuthType implements credentials.AuthInfo.
+func (lp2pAuthInfo) AuthType() string { return "libp2p" }
+
+// SPIFFEID returns the synthetic SPIFFE-like URI for the authenticated peer.
+func (a lp2pAuthInfo) SPIFFEID() string {
+	return spiffeURIPrefix + a.peerID.Pretty()
+}
+
+// lp2pCreds is a credentials.TransportCredentials that performs no handshake
+// of its own (libp2p has already secured the underlying stream) but derives
+// an AuthInfo from the wrapped net.Conn's peer identity, so server
+// interceptors can call peer.FromContext(ctx) and get a verified identity.
+type lp2pCreds struct{}
+
+// NewTransportCredentials returns libp2p-identity-derived transport
+// credentials for use with both NewServer and DialGRPC.
+func NewTransportCredentials() credentials.TransportCredentials {
+	return lp2pCreds{}
+}
+
+func (lp2pCreds) ClientHandshake(ctx context.Context, _ string, conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	authInfo, err := authInfoFromConn(conn)
+	if err != nil {
+		return nil, nil, err
+	}
+	return conn, authInfo, nil
+}
+
+func (lp2pCreds) ServerHandshake(conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	authInfo, err := authInfoFromConn(conn)
+	if err != nil {
+		return nil, nil, err
+	}
+	return conn, authInfo, nil
+}
+
+func (lp2pCreds) Info() credentials.ProtocolInfo {
+	return credentials.ProtocolInfo{SecurityProtocol: "libp2p", SecurityVersion: "1.0"}
+}
+
+func (c lp2pCreds) Clone() credentials.TransportCredentials { return c }
+
+func (lp2pCreds) OverrideServerName(string) error {
+	return fmt.Errorf("OverrideServerName is not supported for libp2p transport credentials")
+}
+
+// authInfoFromConn recovers the remote libp2p PeerID from conn (which must
+// be, or wrap, an lp2pStreamConn) and derives its AuthInfo.
+func authInfoFromConn(conn net.Conn) (credentials.AuthInfo, error) {
+	pidConn, ok := conn.(remotePeerIDer)
+	if !ok {
+		return nil, fmt.Errorf("libp2p transport credentials require a libp2p-backed net.Conn, got %T", conn)
+	}
+	return lp2pAuthInfo{peerID: pidConn.RemotePeerID()}, nil
+}
+
+// authAllowList enforces NodeConfig.TrustedPeers against the verified peer
+// identity connContext attaches to each RPC's context via
+// p2p.ContextWithPeerID. That identity comes from the underlying libp2p
+// stream itself, independent of which grpc.TransportCredentials is in use,
+// so the allow-list keeps working whether or not Security.TLSEnabled has
+// swapped lp2pCreds out for TLS. An empty allow-list permits every peer,
+// matching the "TrustedPeers unset" default elsewhere.
+type authAllowList struct {
+	allowed map[string]struct{}
+}
+
+// AuthInterceptor builds a grpc.UnaryServerInterceptor that enforces trusted
+// against the libp2p peer identity connContext attaches to the RPC's
+// context (see authAllowList). This is the Trust Fabric
+// hook that RegisterAgent's TODO referred to.
+func AuthInterceptor(trusted []config.TrustedPeer) grpc.UnaryServerInterceptor {
+	a := newAuthAllowList(trusted)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := a.check(ctx); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuthInterceptor is the streaming-RPC counterpart of AuthInterceptor,
+// needed because StreamTelemetry is a streaming method.
+func StreamAuthInterceptor(trusted []config.TrustedPeer) grpc.StreamServerInterceptor {
+	a := newAuthAllowList(trusted)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := a.check(ss.Context()); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func newAuthAllowList(trusted []config.TrustedPeer) *authAllowList {
+	allowed := make(map[string]struct{}, len(trusted))
+	for _, tp := range trusted {
+		allowed[tp.PeerID] = struct{}{}
+	}
+	return &authAllowList{allowed: allowed}
+}
+
+func (a *authAllowList) check(ctx context.Context) error {
+	if len(a.allowed) == 0 {
+		return nil
+	}
+	peerID, ok := p2p.PeerIDFromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "no libp2p peer identity in context")
+	}
+	if _, ok := a.allowed[peerID.Pretty()]; !ok {
+		logrus.Warnf("gRPC: rejecting RPC from untrusted peer %s", peerID.Pretty())
+		return status.Errorf(codes.PermissionDenied, "peer %s is not in the trusted allow-list", peerID.Pretty())
+	}
+	return nil
+}
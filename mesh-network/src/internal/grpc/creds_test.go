@@ -0,0 +1,69 @@
+// Copyright 2025 c0r3br34k3r
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/omega-cyber/mesh-network/internal/config"
+	"github.com/omega-cyber/mesh-network/internal/p2p"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPeerIDForCreds(t *testing.T) peer.ID {
+	t.Helper()
+	_, pub, err := crypto.GenerateKeyPair(crypto.Ed25519, -1)
+	require.NoError(t, err)
+	id, err := peer.IDFromPublicKey(pub)
+	require.NoError(t, err)
+	return id
+}
+
+func TestAuthAllowListEmptyAllowsEveryPeer(t *testing.T) {
+	a := newAuthAllowList(nil)
+	assert.NoError(t, a.check(context.Background()))
+}
+
+func TestAuthAllowListRejectsUnauthenticatedContext(t *testing.T) {
+	a := newAuthAllowList([]config.TrustedPeer{{PeerID: "QmTrusted"}})
+	err := a.check(context.Background())
+	assert.Error(t, err)
+}
+
+func TestAuthAllowListRejectsUntrustedPeer(t *testing.T) {
+	a := newAuthAllowList([]config.TrustedPeer{{PeerID: "QmTrusted"}})
+	ctx := p2p.ContextWithPeerID(context.Background(), testPeerIDForCreds(t))
+	err := a.check(ctx)
+	assert.Error(t, err)
+}
+
+// TestAuthAllowListAllowsTrustedPeerRegardlessOfAuthInfoType guards against a
+// regression where check derived the peer identity from a grpc/peer.AuthInfo
+// of concrete type lp2pAuthInfo: that type is only ever produced by lp2pCreds,
+// so enabling Security.TLSEnabled (which swaps in credentials.NewTLS instead)
+// made every RPC fail auth, even from peers on the trusted allow-list. check
+// must instead rely on connContext's p2p.ContextWithPeerID, which is
+// populated the same way no matter which grpc.TransportCredentials is active.
+func TestAuthAllowListAllowsTrustedPeerRegardlessOfAuthInfoType(t *testing.T) {
+	trustedID := testPeerIDForCreds(t)
+	a := newAuthAllowList([]config.TrustedPeer{{PeerID: trustedID.Pretty()}})
+
+	ctx := p2p.ContextWithPeerID(context.Background(), trustedID)
+	assert.NoError(t, a.check(ctx))
+}
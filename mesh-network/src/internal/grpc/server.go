@@ -18,47 +18,145 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net"
 
 	"github.com/omega-cyber/mesh-network/gen/proto/go/mesh"
+	"github.com/omega-cyber/mesh-network/internal/api/admin"
+	"github.com/omega-cyber/mesh-network/internal/config"
 	"github.com/omega-cyber/mesh-network/internal/p2p"
 	"github.com/sirupsen/logrus"
 	"github.com/libp2p/go-libp2p-core/peer"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/health"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/protobuf/proto"
 )
 
-const MeshProtocolID = "/omega/mesh/1.0.0"
+// remotePeerIDer is implemented by the libp2p-backed net.Conn the gRPC
+// server accepts streams on, letting connContext recover the remote
+// PeerID without either package depending on the other's concrete types.
+type remotePeerIDer interface {
+	RemotePeerID() peer.ID
+}
+
+// connContext is installed as the server's grpc.ConnContext hook so every
+// RPC's context carries the remote peer's libp2p PeerID under a typed key,
+// replacing the old "peerid" string-keyed context.WithValue in WrapGRPC.
+func connContext(ctx context.Context, c net.Conn) context.Context {
+	if pidConn, ok := c.(remotePeerIDer); ok {
+		return p2p.ContextWithPeerID(ctx, pidConn.RemotePeerID())
+	}
+	return ctx
+}
+
+// MeshProtocolID is re-exported from internal/p2p, which is where it must
+// live so DialGRPC/WrapGRPC can reference it without an import cycle.
+const MeshProtocolID = p2p.MeshProtocolID
+
+// pexSampleSize bounds how many addresses ExchangeAddresses returns from our
+// own address book per request.
+const pexSampleSize = 32
 
 // Server is the gRPC server for the MeshService.
 type Server struct {
 	mesh.UnimplementedMeshServiceServer
-	node         *p2p.Node
-	grpcServer   *grpc.Server
+	node *p2p.Node
+	// grpcServer is served over libp2p streams only (see Serve): its
+	// transport credentials and AuthInterceptor/StreamAuthInterceptor both
+	// depend on connContext recovering a libp2p peer ID from the accepted
+	// net.Conn, which only a libp2p-backed conn ever satisfies.
+	grpcServer *grpc.Server
+	// tcpServer is the Admin service's listener (see ServeTCP): a plain
+	// *net.TCPConn never satisfies remotePeerIDer, so it carries TLS/mTLS
+	// creds only, with none of grpcServer's libp2p-identity interceptors.
+	tcpServer    *grpc.Server
 	healthServer *health.Server
 }
 
-// NewServer creates a new gRPC server.
-func NewServer(node *p2p.Node) *Server {
+// NewServer creates a new gRPC server. When cfg.Security.TLSEnabled, the
+// libp2p-stream server stacks TLS transport credentials (optionally
+// requiring client certificates) on top of the libp2p stream transport
+// instead of using the libp2p-identity-derived credentials, for deployments
+// that want mTLS enforced independently of libp2p's own stream security. The
+// Admin service's plain TCP server (see ServeTCP) is built and secured
+// separately, since it has no libp2p peer identity to authenticate with.
+func NewServer(node *p2p.Node, cfg *config.Config) (*Server, error) {
+	creds, err := serverCredentials(cfg.Security)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build gRPC server credentials: %w", err)
+	}
+	tcpOpts, err := tcpServerOptions(cfg.Security)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build Admin gRPC server credentials: %w", err)
+	}
+
+	trusted := node.TrustedPeers()
 	s := &Server{
-		node:         node,
-		grpcServer:   grpc.NewServer(),
+		node: node,
+		grpcServer: grpc.NewServer(
+			grpc.ConnContext(connContext),
+			grpc.Creds(creds),
+			grpc.UnaryInterceptor(AuthInterceptor(trusted)),
+			grpc.StreamInterceptor(StreamAuthInterceptor(trusted)),
+		),
+		tcpServer:    grpc.NewServer(tcpOpts...),
 		healthServer: health.NewServer(),
 	}
 
-	// Register the MeshService server and the health server.
+	// Register the MeshService and health server on the libp2p-stream
+	// server; the Admin service belongs on tcpServer (see its doc comment).
 	mesh.RegisterMeshServiceServer(s.grpcServer, s)
 	healthpb.RegisterHealthServer(s.grpcServer, s.healthServer)
+	mesh.RegisterAdminServiceServer(s.tcpServer, admin.NewServer(admin.NewNodeProvider(node)))
+	healthpb.RegisterHealthServer(s.tcpServer, s.healthServer)
 
 	// Enable reflection for gRPCurl and similar tools.
 	reflection.Register(s.grpcServer)
+	reflection.Register(s.tcpServer)
 
 	// Set the serving status for the health check.
 	s.healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
 
-	return s
+	return s, nil
+}
+
+// serverCredentials picks the gRPC transport credentials for the
+// libp2p-stream server based on sec.TLSEnabled: the libp2p-identity-derived
+// credentials by default, or TLS (optionally mutual) built from sec's PEM
+// files.
+func serverCredentials(sec config.SecurityConfig) (credentials.TransportCredentials, error) {
+	if !sec.TLSEnabled {
+		return NewTransportCredentials(), nil
+	}
+
+	tlsCfg, err := config.BuildServerTLS(sec)
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(tlsCfg), nil
+}
+
+// tcpServerOptions builds the grpc.ServerOption(s) for the Admin service's
+// plain TCP listener. Unlike serverCredentials, it never falls back to the
+// libp2p-identity-derived credentials: a raw *net.TCPConn can't satisfy
+// remotePeerIDer, so lp2pCreds' handshake would simply fail every
+// connection, and there is no libp2p peer ID to enforce TrustedPeers
+// against even if the handshake somehow succeeded. With TLS disabled, the
+// Admin TCP listener is plaintext; enable sec.TLSEnabled (optionally with
+// ClientAuthRequired for mTLS) to secure it.
+func tcpServerOptions(sec config.SecurityConfig) ([]grpc.ServerOption, error) {
+	if !sec.TLSEnabled {
+		return nil, nil
+	}
+
+	tlsCfg, err := config.BuildServerTLS(sec)
+	if err != nil {
+		return nil, err
+	}
+	return []grpc.ServerOption{grpc.Creds(credentials.NewTLS(tlsCfg))}, nil
 }
 
 // Serve starts the gRPC server. In this libp2p context, it doesn't listen on a TCP
@@ -68,19 +166,44 @@ func (s *Server) Serve() {
 	logrus.Info("gRPC server is ready to handle streams on protocol: ", MeshProtocolID)
 }
 
-// GracefulStop stops the gRPC server.
+// ServeTCP starts the Admin service listening on a plain TCP socket
+// (typically cfg.Node.GRPCPort), in addition to the libp2p stream handler
+// set up by Serve. This is how operator tooling reaches the node directly,
+// without needing a libp2p connection of its own. It serves s.tcpServer, not
+// s.grpcServer: see tcpServer's doc comment for why the two must stay
+// separate. It returns the listener's address (useful when addr ends in
+// ":0") rather than just an error, so callers and tests can dial it back.
+func (s *Server) ServeTCP(addr string) (net.Addr, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	go func() {
+		if err := s.tcpServer.Serve(listener); err != nil {
+			logrus.WithError(err).Warn("Admin gRPC TCP server stopped serving")
+		}
+	}()
+	logrus.Infof("Admin gRPC server is listening on TCP: %s", listener.Addr())
+	return listener.Addr(), nil
+}
+
+// GracefulStop stops both the libp2p-stream and Admin TCP gRPC servers.
 func (s *Server) GracefulStop() {
 	logrus.Info("Stopping gRPC server...")
 	s.grpcServer.GracefulStop()
+	s.tcpServer.GracefulStop()
 	s.healthServer.Shutdown()
 }
 
 // --- MeshServiceServer Implementation ---
 
-// RegisterAgent handles agent registration. (Placeholder)
+// RegisterAgent handles agent registration. By the time this handler runs,
+// AuthInterceptor has already rejected any peer not in NodeConfig.TrustedPeers,
+// so the agent's libp2p identity has effectively been validated against the
+// Trust Fabric allow-list. (Placeholder for richer session issuance.)
 func (s *Server) RegisterAgent(ctx context.Context, req *mesh.RegisterAgentRequest) (*mesh.RegisterAgentResponse, error) {
 	logrus.Infof("gRPC: Received RegisterAgent request from agent: %s", req.AgentId)
-	// TODO: Integrate with Trust Fabric to validate the agent's public key.
 	return &mesh.RegisterAgentResponse{
 		Success:     true,
 		Message:     fmt.Sprintf("Agent %s registered successfully", req.AgentId),
@@ -90,9 +213,9 @@ func (s *Server) RegisterAgent(ctx context.Context, req *mesh.RegisterAgentReque
 
 // StreamTelemetry handles bi-directional streaming of telemetry and commands.
 func (s *Server) StreamTelemetry(stream mesh.MeshService_StreamTelemetryServer) error {
-	peerID, err := peer.IDFromBytes([]byte(stream.Context().Value("peerid").(string)))
-	if err != nil {
-		return fmt.Errorf("could not get peer id from stream context: %w", err)
+	peerID, ok := p2p.PeerIDFromContext(stream.Context())
+	if !ok {
+		return fmt.Errorf("could not get peer id from stream context")
 	}
 	logrus.Infof("gRPC: Opened telemetry stream from peer: %s", peerID.Pretty())
 
@@ -110,8 +233,68 @@ func (s *Server) StreamTelemetry(stream mesh.MeshService_StreamTelemetryServer)
 
 		logrus.Debugf("gRPC: Received telemetry from %s: Type=%s", telemetry.AgentId, telemetry.Type)
 
-		// TODO: Process telemetry data (e.g., forward to Intelligence Core via Kafka).
+		payload, err := proto.Marshal(telemetry)
+		if err != nil {
+			logrus.WithError(err).Warnf("gRPC: Failed to marshal telemetry from %s for republish", peerID.Pretty())
+			continue
+		}
+		if err := s.node.Publish(stream.Context(), config.DefaultTelemetryTopic, payload); err != nil {
+			logrus.WithError(err).Warnf("gRPC: Failed to republish telemetry from %s", peerID.Pretty())
+		}
+	}
+}
+
+// DiscoverPeers streams peers advertising the requested role as the DHT
+// rendezvous search turns them up, until the client disconnects or the
+// search is exhausted.
+func (s *Server) DiscoverPeers(req *mesh.DiscoverPeersRequest, stream mesh.MeshService_DiscoverPeersServer) error {
+	logrus.Infof("gRPC: Received DiscoverPeers request for role: %s", req.Role)
+
+	found, err := s.node.FindPeers(stream.Context(), req.Role)
+	if err != nil {
+		return fmt.Errorf("failed to find peers for role %q: %w", req.Role, err)
+	}
+
+	for addrInfo := range found {
+		addrs := make([]string, 0, len(addrInfo.Addrs))
+		for _, a := range addrInfo.Addrs {
+			addrs = append(addrs, a.String())
+		}
+		resp := &mesh.GetPeerInfoResponse{
+			Peer: &mesh.PeerInfo{
+				Id:        addrInfo.ID.Pretty(),
+				Addresses: addrs,
+				Status:    mesh.PeerInfo_ONLINE,
+			},
+		}
+		if err := stream.Send(resp); err != nil {
+			return fmt.Errorf("failed to stream discovered peer %s: %w", addrInfo.ID.Pretty(), err)
+		}
 	}
+
+	return nil
+}
+
+// ExchangeAddresses implements a PEX-style handler: it records the
+// addresses the caller reports (attributed to the caller's own PeerID as
+// Src, so a single peer can't poison more than its fair share of our
+// buckets) and returns a random sample from our own address book in return.
+func (s *Server) ExchangeAddresses(ctx context.Context, req *mesh.ExchangeAddressesRequest) (*mesh.ExchangeAddressesResponse, error) {
+	peerID, ok := p2p.PeerIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("could not get peer id from request context")
+	}
+
+	book := s.node.AddressBook()
+	for _, addr := range req.Addresses {
+		if err := book.Add(addr, peerID.Pretty()); err != nil {
+			logrus.WithError(err).Debugf("gRPC: rejected gossiped address %q from %s", addr, peerID.Pretty())
+		}
+	}
+
+	return &mesh.ExchangeAddressesResponse{
+		Addresses: book.Sample(pexSampleSize),
+	}, nil
 }
 
 // GetPeerInfo retrieves information about a specific peer. (Placeholder)
@@ -0,0 +1,74 @@
+// Copyright 2025 c0r3br34k3r
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/omega-cyber/mesh-network/gen/proto/go/mesh"
+	"github.com/omega-cyber/mesh-network/internal/config"
+	omegagrpc "github.com/omega-cyber/mesh-network/internal/grpc"
+	"github.com/omega-cyber/mesh-network/internal/p2p"
+	"github.com/stretchr/testify/require"
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// TestServeTCPAdminServiceReachableOverPlainTCP exercises the real, fully
+// wired Server (libp2p-identity transport credentials and interceptors
+// included, same as NewServer builds in production) rather than the bare
+// grpc.NewServer() admin_test.go's dialAdmin uses. It guards against a
+// regression where ServeTCP served the Admin service on the same
+// s.grpcServer used for libp2p streams: that server's transport credentials
+// (and the peer-identity interceptors layered on top) require a net.Conn
+// that carries a libp2p peer ID, which a plain *net.TCPConn never does, so
+// every Admin RPC over TCP failed outright.
+func TestServeTCPAdminServiceReachableOverPlainTCP(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	nodeCfg := config.NodeConfig{ID: "tcp-admin-node", P2PPort: 0, GRPCPort: 0}
+	node, err := p2p.NewNode(ctx, &nodeCfg)
+	require.NoError(t, err)
+	defer node.Close()
+
+	server, err := omegagrpc.NewServer(node, &config.Config{Node: nodeCfg})
+	require.NoError(t, err)
+	server.Serve()
+	defer server.GracefulStop()
+
+	addr, err := server.ServeTCP("127.0.0.1:0")
+	require.NoError(t, err)
+
+	// Connect one peer so PeerInfoStream's initial snapshot has an event to
+	// send; with no peers the stream sends nothing until a peer connects.
+	peerNode, err := p2p.NewNode(ctx, &config.NodeConfig{ID: "tcp-admin-peer", P2PPort: 0, GRPCPort: 0})
+	require.NoError(t, err)
+	defer peerNode.Close()
+	require.NoError(t, node.Host.Connect(ctx, peerNode.Host.Peerstore().PeerInfo(peerNode.Host.ID())))
+
+	conn, err := gogrpc.DialContext(ctx, addr.String(), gogrpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client := mesh.NewAdminServiceClient(conn)
+	stream, err := client.PeerInfoStream(ctx, &mesh.PeerInfoStreamRequest{})
+	require.NoError(t, err)
+
+	_, err = stream.Recv()
+	require.NoError(t, err, "Admin service must be reachable over plain TCP without a libp2p peer identity")
+}
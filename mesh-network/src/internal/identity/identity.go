@@ -0,0 +1,176 @@
+// Copyright 2025 c0r3br34k3r
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package identity loads and persists the Ed25519 private key that gives a
+// mesh node its stable libp2p PeerID across restarts.
+package identity
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// keyFilePerm restricts the on-disk key to the owning user; the key
+	// grants the node's entire mesh identity so it must never be world- or
+	// group-readable.
+	keyFilePerm = 0o600
+
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+	saltLen      = 16
+	nonceLen     = 12
+
+	// plainMagic marks a key file that was written without a passphrase, so
+	// Load can tell an unencrypted key apart from scrypt+AES-GCM ciphertext.
+	plainMagic = "OMEGAKEY1\n"
+)
+
+// LoadOrCreate loads the Ed25519 private key at path, decrypting it with
+// passphrase if non-empty. If no key exists at path, a new one is generated
+// and persisted there. If path is empty, a fresh key is generated and
+// returned without ever touching disk.
+func LoadOrCreate(path, passphrase string) (crypto.PrivKey, error) {
+	if path == "" {
+		priv, _, err := crypto.GenerateKeyPair(crypto.Ed25519, -1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate ephemeral key pair: %w", err)
+		}
+		return priv, nil
+	}
+
+	raw, err := os.ReadFile(path)
+	if err == nil {
+		return decode(raw, passphrase)
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read identity file %s: %w", path, err)
+	}
+
+	priv, _, err := crypto.GenerateKeyPair(crypto.Ed25519, -1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key pair: %w", err)
+	}
+	if err := save(path, priv, passphrase); err != nil {
+		return nil, err
+	}
+	return priv, nil
+}
+
+func save(path string, priv crypto.PrivKey, passphrase string) error {
+	keyBytes, err := crypto.MarshalPrivateKey(priv)
+	if err != nil {
+		return fmt.Errorf("failed to marshal private key: %w", err)
+	}
+
+	var out []byte
+	if passphrase == "" {
+		out = append([]byte(plainMagic), keyBytes...)
+	} else {
+		out, err = encrypt(keyBytes, passphrase)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt identity key: %w", err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create identity directory: %w", err)
+	}
+	if err := os.WriteFile(path, out, keyFilePerm); err != nil {
+		return fmt.Errorf("failed to write identity file %s: %w", path, err)
+	}
+	return nil
+}
+
+func decode(raw []byte, passphrase string) (crypto.PrivKey, error) {
+	var keyBytes []byte
+	if len(raw) >= len(plainMagic) && string(raw[:len(plainMagic)]) == plainMagic {
+		keyBytes = raw[len(plainMagic):]
+	} else {
+		var err error
+		keyBytes, err = decrypt(raw, passphrase)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt identity key: %w", err)
+		}
+	}
+
+	priv, err := crypto.UnmarshalPrivateKey(keyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal private key: %w", err)
+	}
+	return priv, nil
+}
+
+// encrypt wraps keyBytes as salt || nonce || ciphertext, where the AES-GCM
+// key is derived from passphrase via scrypt using salt.
+func encrypt(keyBytes []byte, passphrase string) ([]byte, error) {
+	salt := make([]byte, saltLen)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	derived, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, scryptKeyLen)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(derived)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, nonceLen)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	ciphertext := gcm.Seal(nil, nonce, keyBytes, nil)
+
+	out := make([]byte, 0, saltLen+nonceLen+len(ciphertext))
+	out = append(out, salt...)
+	out = append(out, nonce...)
+	out = append(out, ciphertext...)
+	return out, nil
+}
+
+func decrypt(data []byte, passphrase string) ([]byte, error) {
+	if len(data) < saltLen+nonceLen {
+		return nil, fmt.Errorf("identity file is too short to be a valid encrypted key")
+	}
+	salt, nonce, ciphertext := data[:saltLen], data[saltLen:saltLen+nonceLen], data[saltLen+nonceLen:]
+
+	derived, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, scryptKeyLen)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(derived)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
@@ -0,0 +1,60 @@
+// Copyright 2025 c0r3br34k3r
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package identity_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/omega-cyber/mesh-network/internal/identity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadOrCreateEphemeral(t *testing.T) {
+	priv1, err := identity.LoadOrCreate("", "")
+	require.NoError(t, err)
+
+	priv2, err := identity.LoadOrCreate("", "")
+	require.NoError(t, err)
+
+	assert.False(t, priv1.Equals(priv2), "ephemeral identities must not be reused across calls")
+}
+
+func TestLoadOrCreatePersistsAndReloadsUnencrypted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "identity.key")
+
+	priv1, err := identity.LoadOrCreate(path, "")
+	require.NoError(t, err)
+
+	priv2, err := identity.LoadOrCreate(path, "")
+	require.NoError(t, err)
+
+	assert.True(t, priv1.Equals(priv2), "reloading the same key file must return the same key")
+}
+
+func TestLoadOrCreateEncryptedRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "identity.key")
+
+	priv1, err := identity.LoadOrCreate(path, "correct horse battery staple")
+	require.NoError(t, err)
+
+	priv2, err := identity.LoadOrCreate(path, "correct horse battery staple")
+	require.NoError(t, err)
+	assert.True(t, priv1.Equals(priv2))
+
+	_, err = identity.LoadOrCreate(path, "wrong passphrase")
+	assert.Error(t, err)
+}
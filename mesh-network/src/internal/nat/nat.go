@@ -0,0 +1,152 @@
+// Copyright 2025 c0r3br34k3r
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package nat maps the node's P2P listen port through a home router or
+// similar NAT, via UPnP (discovered over SSDP) with a NAT-PMP fallback, so
+// the node can accept inbound connections without manual port forwarding.
+package nat
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// discoveryTimeout bounds how long Discover waits for a UPnP IGD to
+// respond before falling back to NAT-PMP.
+const discoveryTimeout = 3 * time.Second
+
+// Gateway is a NAT device capable of mapping ports and reporting the
+// public IP it maps them to. upnpGateway and natpmpGateway implement it.
+type Gateway interface {
+	AddPortMapping(ctx context.Context, internalPort, externalPort int, proto string, lease time.Duration) error
+	DeletePortMapping(ctx context.Context, externalPort int, proto string) error
+	ExternalIP(ctx context.Context) (net.IP, error)
+}
+
+// Mapper owns a live port mapping on a discovered Gateway and keeps it
+// renewed until Unmap is called.
+type Mapper struct {
+	gw Gateway
+
+	mu           sync.Mutex
+	externalIP   net.IP
+	internalPort int
+	externalPort int
+	proto        string
+	lease        time.Duration
+	cancelRenew  context.CancelFunc
+}
+
+// Discover finds a NAT gateway on the local network, trying UPnP (SSDP)
+// first and falling back to NAT-PMP if no UPnP IGD responds within
+// discoveryTimeout.
+func Discover(ctx context.Context) (Gateway, error) {
+	discoverCtx, cancel := context.WithTimeout(ctx, discoveryTimeout)
+	defer cancel()
+
+	gw, err := discoverUPnP(discoverCtx)
+	if err == nil {
+		return gw, nil
+	}
+	logrus.WithError(err).Debug("nat: no UPnP IGD found, falling back to NAT-PMP")
+
+	return discoverNATPMP(ctx)
+}
+
+// Map discovers a gateway with Discover and maps internalPort to
+// externalPort for proto ("tcp" or "udp"), renewing the mapping at lease/2
+// intervals in the background until Unmap is called. MapWithGateway takes
+// an already-discovered Gateway instead.
+func Map(ctx context.Context, internalPort, externalPort int, proto string, lease time.Duration) (*Mapper, error) {
+	gw, err := Discover(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("nat: gateway discovery failed: %w", err)
+	}
+	return MapWithGateway(ctx, gw, internalPort, externalPort, proto, lease)
+}
+
+// MapWithGateway is Map with an already-discovered Gateway, primarily so
+// tests can inject a fake one.
+func MapWithGateway(ctx context.Context, gw Gateway, internalPort, externalPort int, proto string, lease time.Duration) (*Mapper, error) {
+	if err := gw.AddPortMapping(ctx, internalPort, externalPort, proto, lease); err != nil {
+		return nil, fmt.Errorf("nat: failed to map port %d/%s: %w", externalPort, proto, err)
+	}
+
+	externalIP, err := gw.ExternalIP(ctx)
+	if err != nil {
+		logrus.WithError(err).Warn("nat: mapped port but failed to query external IP")
+	}
+
+	renewCtx, cancel := context.WithCancel(context.Background())
+	m := &Mapper{
+		gw:           gw,
+		externalIP:   externalIP,
+		internalPort: internalPort,
+		externalPort: externalPort,
+		proto:        proto,
+		lease:        lease,
+		cancelRenew:  cancel,
+	}
+	go m.renewLoop(renewCtx)
+
+	return m, nil
+}
+
+// ExternalIP returns the public IP address the gateway reported when the
+// mapping was created (or last renewed).
+func (m *Mapper) ExternalIP() net.IP {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.externalIP
+}
+
+func (m *Mapper) renewLoop(ctx context.Context) {
+	if m.lease <= 0 {
+		return
+	}
+	ticker := time.NewTicker(m.lease / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			renewCtx, cancel := context.WithTimeout(ctx, discoveryTimeout)
+			err := m.gw.AddPortMapping(renewCtx, m.internalPort, m.externalPort, m.proto, m.lease)
+			if err == nil {
+				if ip, ipErr := m.gw.ExternalIP(renewCtx); ipErr == nil {
+					m.mu.Lock()
+					m.externalIP = ip
+					m.mu.Unlock()
+				}
+			}
+			cancel()
+			if err != nil {
+				logrus.WithError(err).Warnf("nat: failed to renew port mapping %d/%s", m.externalPort, m.proto)
+			}
+		}
+	}
+}
+
+// Unmap stops the background renewer and removes the port mapping.
+func (m *Mapper) Unmap(ctx context.Context) error {
+	m.cancelRenew()
+	return m.gw.DeletePortMapping(ctx, m.externalPort, m.proto)
+}
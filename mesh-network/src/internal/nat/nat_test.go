@@ -0,0 +1,126 @@
+// Copyright 2025 c0r3br34k3r
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nat
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeGateway is an in-memory Gateway used to exercise Mapper's renewal and
+// unmap logic without any real network device.
+type fakeGateway struct {
+	mu         sync.Mutex
+	mapped     bool
+	renewals   int32
+	externalIP net.IP
+	unmapped   chan struct{}
+}
+
+func newFakeGateway() *fakeGateway {
+	return &fakeGateway{
+		externalIP: net.ParseIP("203.0.113.7"),
+		unmapped:   make(chan struct{}, 1),
+	}
+}
+
+func (g *fakeGateway) AddPortMapping(ctx context.Context, internalPort, externalPort int, proto string, lease time.Duration) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.mapped {
+		atomic.AddInt32(&g.renewals, 1)
+	}
+	g.mapped = true
+	return nil
+}
+
+func (g *fakeGateway) DeletePortMapping(ctx context.Context, externalPort int, proto string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.mapped = false
+	select {
+	case g.unmapped <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (g *fakeGateway) ExternalIP(ctx context.Context) (net.IP, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.externalIP, nil
+}
+
+func TestMapWithGatewaySetsExternalIP(t *testing.T) {
+	gw := newFakeGateway()
+
+	m, err := MapWithGateway(context.Background(), gw, 7946, 7946, "tcp", time.Hour)
+	if err != nil {
+		t.Fatalf("MapWithGateway failed: %v", err)
+	}
+	defer m.Unmap(context.Background())
+
+	if got := m.ExternalIP(); got.String() != "203.0.113.7" {
+		t.Fatalf("ExternalIP() = %s, want 203.0.113.7", got)
+	}
+}
+
+func TestMapperRenewsBeforeLeaseExpires(t *testing.T) {
+	gw := newFakeGateway()
+
+	m, err := MapWithGateway(context.Background(), gw, 7946, 7946, "udp", 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("MapWithGateway failed: %v", err)
+	}
+	defer m.Unmap(context.Background())
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if atomic.LoadInt32(&gw.renewals) >= 2 {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("expected at least 2 renewals, got %d", atomic.LoadInt32(&gw.renewals))
+}
+
+func TestUnmapStopsRenewalAndDeletesMapping(t *testing.T) {
+	gw := newFakeGateway()
+
+	m, err := MapWithGateway(context.Background(), gw, 7946, 7946, "tcp", 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("MapWithGateway failed: %v", err)
+	}
+
+	if err := m.Unmap(context.Background()); err != nil {
+		t.Fatalf("Unmap failed: %v", err)
+	}
+
+	select {
+	case <-gw.unmapped:
+	case <-time.After(time.Second):
+		t.Fatal("DeletePortMapping was not called by Unmap")
+	}
+
+	renewalsAtUnmap := atomic.LoadInt32(&gw.renewals)
+	time.Sleep(200 * time.Millisecond)
+	if got := atomic.LoadInt32(&gw.renewals); got != renewalsAtUnmap {
+		t.Fatalf("renewals continued after Unmap: %d -> %d", renewalsAtUnmap, got)
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2025 c0r3br34k3r
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nat
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	natPMPPort        = 5351
+	natPMPVersion     = 0
+	opExternalAddress = 0
+	opMapUDP          = 1
+	opMapTCP          = 2
+
+	natPMPRequestTimeout = 250 * time.Millisecond
+	natPMPMaxRetries     = 4
+)
+
+// natpmpGateway implements Gateway against the default gateway's NAT-PMP
+// service, used as a fallback when no UPnP IGD responds.
+type natpmpGateway struct {
+	gatewayAddr *net.UDPAddr
+}
+
+// discoverNATPMP finds the default gateway and confirms it speaks NAT-PMP by
+// issuing an external-address query.
+func discoverNATPMP(ctx context.Context) (*natpmpGateway, error) {
+	gwIP, err := defaultGatewayIP()
+	if err != nil {
+		return nil, fmt.Errorf("nat: failed to determine default gateway: %w", err)
+	}
+
+	gw := &natpmpGateway{
+		gatewayAddr: &net.UDPAddr{IP: gwIP, Port: natPMPPort},
+	}
+
+	if _, err := gw.ExternalIP(ctx); err != nil {
+		return nil, fmt.Errorf("nat: default gateway %s does not speak NAT-PMP: %w", gwIP, err)
+	}
+	return gw, nil
+}
+
+// defaultGatewayIP reads the kernel routing table to find the default
+// route's gateway address.
+func defaultGatewayIP() (net.IP, error) {
+	f, err := os.Open("/proc/net/route")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // header line
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 {
+			continue
+		}
+		destHex, gatewayHex := fields[1], fields[2]
+		if destHex != "00000000" {
+			continue
+		}
+		return parseHexLittleEndianIP(gatewayHex)
+	}
+	return nil, fmt.Errorf("nat: no default route found in /proc/net/route")
+}
+
+// parseHexLittleEndianIP decodes a little-endian hex-encoded IPv4 address,
+// the format /proc/net/route stores gateway and destination fields in.
+func parseHexLittleEndianIP(hexAddr string) (net.IP, error) {
+	var raw uint32
+	if _, err := fmt.Sscanf(hexAddr, "%x", &raw); err != nil {
+		return nil, fmt.Errorf("nat: malformed route table address %q: %w", hexAddr, err)
+	}
+	ip := make(net.IP, 4)
+	binary.LittleEndian.PutUint32(ip, raw)
+	return ip, nil
+}
+
+// request sends a NAT-PMP opcode request and returns the raw response
+// payload, retrying with a short timeout per the NAT-PMP spec's recommended
+// retransmission schedule.
+func (g *natpmpGateway) request(ctx context.Context, payload []byte) ([]byte, error) {
+	conn, err := net.DialUDP("udp4", nil, g.gatewayAddr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	timeout := natPMPRequestTimeout
+	var lastErr error
+	buf := make([]byte, 16)
+
+	for attempt := 0; attempt < natPMPMaxRetries; attempt++ {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		if _, err := conn.Write(payload); err != nil {
+			return nil, err
+		}
+		conn.SetReadDeadline(time.Now().Add(timeout))
+
+		n, err := conn.Read(buf)
+		if err != nil {
+			lastErr = err
+			timeout *= 2
+			continue
+		}
+		return buf[:n], nil
+	}
+	return nil, fmt.Errorf("nat: NAT-PMP request to %s timed out: %w", g.gatewayAddr, lastErr)
+}
+
+func (g *natpmpGateway) ExternalIP(ctx context.Context) (net.IP, error) {
+	resp, err := g.request(ctx, []byte{natPMPVersion, opExternalAddress})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp) < 12 {
+		return nil, fmt.Errorf("nat: NAT-PMP external address response too short")
+	}
+	if resultCode := binary.BigEndian.Uint16(resp[2:4]); resultCode != 0 {
+		return nil, fmt.Errorf("nat: NAT-PMP external address request failed: result code %d", resultCode)
+	}
+	return net.IP(resp[8:12]), nil
+}
+
+func (g *natpmpGateway) AddPortMapping(ctx context.Context, internalPort, externalPort int, proto string, lease time.Duration) error {
+	op := byte(opMapUDP)
+	if strings.EqualFold(proto, "tcp") {
+		op = opMapTCP
+	}
+
+	payload := make([]byte, 12)
+	payload[0] = natPMPVersion
+	payload[1] = op
+	binary.BigEndian.PutUint16(payload[4:6], uint16(internalPort))
+	binary.BigEndian.PutUint16(payload[6:8], uint16(externalPort))
+	binary.BigEndian.PutUint32(payload[8:12], uint32(lease.Seconds()))
+
+	resp, err := g.request(ctx, payload)
+	if err != nil {
+		return err
+	}
+	if len(resp) < 16 {
+		return fmt.Errorf("nat: NAT-PMP map response too short")
+	}
+	if resultCode := binary.BigEndian.Uint16(resp[2:4]); resultCode != 0 {
+		return fmt.Errorf("nat: NAT-PMP map request failed: result code %d", resultCode)
+	}
+	return nil
+}
+
+func (g *natpmpGateway) DeletePortMapping(ctx context.Context, externalPort int, proto string) error {
+	op := byte(opMapUDP)
+	if strings.EqualFold(proto, "tcp") {
+		op = opMapTCP
+	}
+
+	// A mapping is removed by requesting it again with a zero lifetime, per
+	// the NAT-PMP spec.
+	payload := make([]byte, 12)
+	payload[0] = natPMPVersion
+	payload[1] = op
+	binary.BigEndian.PutUint16(payload[6:8], uint16(externalPort))
+
+	resp, err := g.request(ctx, payload)
+	if err != nil {
+		return err
+	}
+	if len(resp) < 16 {
+		return fmt.Errorf("nat: NAT-PMP unmap response too short")
+	}
+	if resultCode := binary.BigEndian.Uint16(resp[2:4]); resultCode != 0 {
+		return fmt.Errorf("nat: NAT-PMP unmap request failed: result code %d", resultCode)
+	}
+	return nil
+}
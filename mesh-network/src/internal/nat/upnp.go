@@ -0,0 +1,290 @@
+// Copyright 2025 c0r3br34k3r
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nat
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ssdpAddr         = "239.255.255.250:1900"
+	ssdpSearchTarget = "urn:schemas-upnp-org:device:InternetGatewayDevice:1"
+	wanIPServiceType = "urn:schemas-upnp-org:service:WANIPConnection:1"
+)
+
+// upnpGateway implements Gateway against a discovered Internet Gateway
+// Device's WANIPConnection service, via SOAP-over-HTTP requests.
+type upnpGateway struct {
+	controlURL  string
+	serviceType string
+	client      *http.Client
+}
+
+// discoverUPnP sends an SSDP M-SEARCH for an InternetGatewayDevice, fetches
+// the first responder's device description XML, and resolves its
+// WANIPConnection control URL.
+func discoverUPnP(ctx context.Context) (*upnpGateway, error) {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{})
+	if err != nil {
+		return nil, fmt.Errorf("nat: failed to open SSDP socket: %w", err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	dest, err := net.ResolveUDPAddr("udp4", ssdpAddr)
+	if err != nil {
+		return nil, fmt.Errorf("nat: failed to resolve SSDP multicast address: %w", err)
+	}
+
+	req := "M-SEARCH * HTTP/1.1\r\n" +
+		"HOST: " + ssdpAddr + "\r\n" +
+		"MAN: \"ssdp:discover\"\r\n" +
+		"MX: 2\r\n" +
+		"ST: " + ssdpSearchTarget + "\r\n\r\n"
+	if _, err := conn.WriteTo([]byte(req), dest); err != nil {
+		return nil, fmt.Errorf("nat: failed to send SSDP M-SEARCH: %w", err)
+	}
+
+	buf := make([]byte, 4096)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			return nil, fmt.Errorf("nat: no SSDP response: %w", err)
+		}
+
+		location, err := parseSSDPLocation(buf[:n])
+		if err != nil {
+			continue
+		}
+
+		gw, err := fetchUPnPGateway(ctx, location)
+		if err != nil {
+			continue
+		}
+		return gw, nil
+	}
+}
+
+// parseSSDPLocation extracts the LOCATION header from a raw SSDP response.
+func parseSSDPLocation(resp []byte) (string, error) {
+	r := bufio.NewReader(bytes.NewReader(resp))
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			break
+		}
+		line = strings.TrimSpace(line)
+		if idx := strings.IndexByte(line, ':'); idx > 0 {
+			key := strings.ToUpper(strings.TrimSpace(line[:idx]))
+			if key == "LOCATION" {
+				return strings.TrimSpace(line[idx+1:]), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("nat: no LOCATION header in SSDP response")
+}
+
+// upnpDevice is the subset of a UPnP device description XML document this
+// package needs to locate the WANIPConnection control URL.
+type upnpDevice struct {
+	XMLName xml.Name `xml:"root"`
+	Device  struct {
+		DeviceList struct {
+			Device []struct {
+				DeviceList struct {
+					Device []struct {
+						ServiceList struct {
+							Service []upnpService `xml:"service"`
+						} `xml:"serviceList"`
+					} `xml:"device"`
+				} `xml:"deviceList"`
+				ServiceList struct {
+					Service []upnpService `xml:"service"`
+				} `xml:"serviceList"`
+			} `xml:"device"`
+		} `xml:"deviceList"`
+		ServiceList struct {
+			Service []upnpService `xml:"service"`
+		} `xml:"serviceList"`
+	} `xml:"device"`
+}
+
+type upnpService struct {
+	ServiceType string `xml:"serviceType"`
+	ControlURL  string `xml:"controlURL"`
+}
+
+// fetchUPnPGateway fetches the device description at location and resolves
+// the WANIPConnection service's control URL from it.
+func fetchUPnPGateway(ctx context.Context, location string) (*upnpGateway, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var doc upnpDevice
+	if err := xml.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("nat: failed to parse device description: %w", err)
+	}
+
+	svc, ok := findWANIPService(doc)
+	if !ok {
+		return nil, fmt.Errorf("nat: no WANIPConnection service in device description")
+	}
+
+	base, err := url.Parse(location)
+	if err != nil {
+		return nil, err
+	}
+	controlURL, err := base.Parse(svc.ControlURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &upnpGateway{
+		controlURL:  controlURL.String(),
+		serviceType: svc.ServiceType,
+		client:      &http.Client{Timeout: 5 * time.Second},
+	}, nil
+}
+
+func findWANIPService(doc upnpDevice) (upnpService, bool) {
+	candidates := doc.Device.ServiceList.Service
+	for _, d := range doc.Device.DeviceList.Device {
+		candidates = append(candidates, d.ServiceList.Service...)
+		for _, d2 := range d.DeviceList.Device {
+			candidates = append(candidates, d2.ServiceList.Service...)
+		}
+	}
+	for _, svc := range candidates {
+		if strings.HasPrefix(svc.ServiceType, "urn:schemas-upnp-org:service:WANIPConnection:") {
+			return svc, true
+		}
+	}
+	return upnpService{}, false
+}
+
+// soapCall issues a SOAP request for action against the gateway's control
+// URL with the given arguments (name/value pairs, in order), returning the
+// raw response body for the caller to parse.
+func (g *upnpGateway) soapCall(ctx context.Context, action string, args [][2]string) ([]byte, error) {
+	var body strings.Builder
+	body.WriteString(`<?xml version="1.0"?>`)
+	body.WriteString(`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/">`)
+	body.WriteString(`<s:Body><u:` + action + ` xmlns:u="` + g.serviceType + `">`)
+	for _, kv := range args {
+		body.WriteString("<" + kv[0] + ">" + kv[1] + "</" + kv[0] + ">")
+	}
+	body.WriteString(`</u:` + action + `></s:Body></s:Envelope>`)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.controlURL, strings.NewReader(body.String()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", `text/xml; charset="utf-8"`)
+	req.Header.Set("SOAPAction", `"`+g.serviceType+"#"+action+`"`)
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	buf := new(bytes.Buffer)
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("nat: SOAP %s failed: HTTP %d: %s", action, resp.StatusCode, buf.String())
+	}
+	return buf.Bytes(), nil
+}
+
+func (g *upnpGateway) AddPortMapping(ctx context.Context, internalPort, externalPort int, proto string, lease time.Duration) error {
+	_, err := g.soapCall(ctx, "AddPortMapping", [][2]string{
+		{"NewRemoteHost", ""},
+		{"NewExternalPort", strconv.Itoa(externalPort)},
+		{"NewProtocol", strings.ToUpper(proto)},
+		{"NewInternalPort", strconv.Itoa(internalPort)},
+		{"NewInternalClient", localIPv4()},
+		{"NewEnabled", "1"},
+		{"NewPortMappingDescription", "omega-mesh"},
+		{"NewLeaseDuration", strconv.Itoa(int(lease.Seconds()))},
+	})
+	return err
+}
+
+func (g *upnpGateway) DeletePortMapping(ctx context.Context, externalPort int, proto string) error {
+	_, err := g.soapCall(ctx, "DeletePortMapping", [][2]string{
+		{"NewRemoteHost", ""},
+		{"NewExternalPort", strconv.Itoa(externalPort)},
+		{"NewProtocol", strings.ToUpper(proto)},
+	})
+	return err
+}
+
+type externalIPResponse struct {
+	Body struct {
+		GetExternalIPAddressResponse struct {
+			NewExternalIPAddress string `xml:"NewExternalIPAddress"`
+		} `xml:"GetExternalIPAddressResponse"`
+	} `xml:"Body"`
+}
+
+func (g *upnpGateway) ExternalIP(ctx context.Context) (net.IP, error) {
+	respBody, err := g.soapCall(ctx, "GetExternalIPAddress", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var env externalIPResponse
+	if err := xml.Unmarshal(respBody, &env); err != nil {
+		return nil, fmt.Errorf("nat: failed to parse GetExternalIPAddress response: %w", err)
+	}
+	ip := net.ParseIP(env.Body.GetExternalIPAddressResponse.NewExternalIPAddress)
+	if ip == nil {
+		return nil, fmt.Errorf("nat: gateway returned invalid external IP")
+	}
+	return ip, nil
+}
+
+// localIPv4 returns this host's best-guess outbound IPv4 address, for the
+// NewInternalClient SOAP argument.
+func localIPv4() string {
+	conn, err := net.Dial("udp4", "8.8.8.8:80")
+	if err != nil {
+		return "0.0.0.0"
+	}
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).IP.String()
+}
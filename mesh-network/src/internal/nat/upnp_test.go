@@ -0,0 +1,140 @@
+// Copyright 2025 c0r3br34k3r
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nat
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const fakeDeviceDescriptionXML = `<?xml version="1.0"?>
+<root xmlns="urn:schemas-upnp-org:device-1-0">
+  <device>
+    <deviceType>urn:schemas-upnp-org:device:InternetGatewayDevice:1</deviceType>
+    <deviceList>
+      <device>
+        <deviceType>urn:schemas-upnp-org:device:WANDevice:1</deviceType>
+        <deviceList>
+          <device>
+            <deviceType>urn:schemas-upnp-org:device:WANConnectionDevice:1</deviceType>
+            <serviceList>
+              <service>
+                <serviceType>urn:schemas-upnp-org:service:WANIPConnection:1</serviceType>
+                <controlURL>/ctl/IPConn</controlURL>
+              </service>
+            </serviceList>
+          </device>
+        </deviceList>
+      </device>
+    </deviceList>
+  </device>
+</root>`
+
+// newFakeIGDServer serves a fake IGD device description and control
+// endpoint; soapResponses maps a SOAP action name to the response body the
+// control endpoint returns for it.
+func newFakeIGDServer(t *testing.T, soapResponses map[string]string) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/desc.xml", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xml")
+		w.Write([]byte(fakeDeviceDescriptionXML))
+	})
+	mux.HandleFunc("/ctl/IPConn", func(w http.ResponseWriter, r *http.Request) {
+		action := r.Header.Get("SOAPAction")
+		for name, body := range soapResponses {
+			if contains(action, name) {
+				w.Header().Set("Content-Type", "text/xml")
+				w.Write([]byte(body))
+				return
+			}
+		}
+		http.Error(w, "unknown SOAP action", http.StatusInternalServerError)
+	})
+	return httptest.NewServer(mux)
+}
+
+func contains(haystack, needle string) bool {
+	return len(haystack) >= len(needle) && (haystack == needle || indexOf(haystack, needle) >= 0)
+}
+
+func indexOf(haystack, needle string) int {
+	for i := 0; i+len(needle) <= len(haystack); i++ {
+		if haystack[i:i+len(needle)] == needle {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestFetchUPnPGatewayResolvesControlURL(t *testing.T) {
+	srv := newFakeIGDServer(t, nil)
+	defer srv.Close()
+
+	gw, err := fetchUPnPGateway(context.Background(), srv.URL+"/desc.xml")
+	if err != nil {
+		t.Fatalf("fetchUPnPGateway failed: %v", err)
+	}
+	want := srv.URL + "/ctl/IPConn"
+	if gw.controlURL != want {
+		t.Fatalf("controlURL = %s, want %s", gw.controlURL, want)
+	}
+	if gw.serviceType != wanIPServiceType {
+		t.Fatalf("serviceType = %s, want %s", gw.serviceType, wanIPServiceType)
+	}
+}
+
+func TestUPnPGatewayExternalIP(t *testing.T) {
+	respXML := `<?xml version="1.0"?>
+<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/">
+  <s:Body>
+    <u:GetExternalIPAddressResponse xmlns:u="urn:schemas-upnp-org:service:WANIPConnection:1">
+      <NewExternalIPAddress>198.51.100.23</NewExternalIPAddress>
+    </u:GetExternalIPAddressResponse>
+  </s:Body>
+</s:Envelope>`
+	srv := newFakeIGDServer(t, map[string]string{"GetExternalIPAddress": respXML})
+	defer srv.Close()
+
+	gw, err := fetchUPnPGateway(context.Background(), srv.URL+"/desc.xml")
+	if err != nil {
+		t.Fatalf("fetchUPnPGateway failed: %v", err)
+	}
+
+	ip, err := gw.ExternalIP(context.Background())
+	if err != nil {
+		t.Fatalf("ExternalIP failed: %v", err)
+	}
+	if ip.String() != "198.51.100.23" {
+		t.Fatalf("ExternalIP() = %s, want 198.51.100.23", ip)
+	}
+}
+
+func TestUPnPGatewayAddPortMappingPropagatesFailure(t *testing.T) {
+	srv := newFakeIGDServer(t, nil) // unknown action -> 500
+	defer srv.Close()
+
+	gw, err := fetchUPnPGateway(context.Background(), srv.URL+"/desc.xml")
+	if err != nil {
+		t.Fatalf("fetchUPnPGateway failed: %v", err)
+	}
+
+	err = gw.AddPortMapping(context.Background(), 7946, 7946, "tcp", 0)
+	if err == nil {
+		t.Fatal("expected AddPortMapping to fail against a server with no handler for it")
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 c0r3br34k3r
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package p2p
+
+import (
+	"context"
+	"net"
+
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/multiformats/go-multiaddr"
+)
+
+// peerIDContextKey is a typed context key so the remote libp2p PeerID can be
+// carried on a gRPC connection's context without colliding with other
+// packages that also stash values under a plain string key.
+type peerIDContextKey struct{}
+
+// ContextWithPeerID returns a copy of ctx carrying peerID, retrievable with
+// PeerIDFromContext. It is exported so internal/grpc can use it as the base
+// context for a gRPC server's ConnContext hook.
+func ContextWithPeerID(ctx context.Context, peerID peer.ID) context.Context {
+	return context.WithValue(ctx, peerIDContextKey{}, peerID)
+}
+
+// PeerIDFromContext extracts the remote libp2p PeerID previously attached by
+// ContextWithPeerID, such as from a gRPC stream's context.
+func PeerIDFromContext(ctx context.Context) (peer.ID, bool) {
+	peerID, ok := ctx.Value(peerIDContextKey{}).(peer.ID)
+	return peerID, ok
+}
+
+// multiaddrNetAddr adapts a multiaddr.Multiaddr to the net.Addr interface so
+// it can be returned from lp2pStreamConn's LocalAddr/RemoteAddr.
+type multiaddrNetAddr struct {
+	maddr multiaddr.Multiaddr
+}
+
+func (a multiaddrNetAddr) Network() string { return "libp2p" }
+func (a multiaddrNetAddr) String() string  { return a.maddr.String() }
+
+// lp2pStreamConn is a net.Conn implementation that wraps a libp2p stream.
+// Read, Write, Close, and the deadline methods are all satisfied by the
+// embedded network.Stream, which already implements them over the
+// underlying muxed connection.
+type lp2pStreamConn struct {
+	network.Stream
+}
+
+// LocalAddr returns the local multiaddr of the underlying libp2p connection.
+func (c *lp2pStreamConn) LocalAddr() net.Addr {
+	return multiaddrNetAddr{maddr: c.Conn().LocalMultiaddr()}
+}
+
+// RemoteAddr returns the remote multiaddr of the underlying libp2p connection.
+func (c *lp2pStreamConn) RemoteAddr() net.Addr {
+	return multiaddrNetAddr{maddr: c.Conn().RemoteMultiaddr()}
+}
+
+// RemotePeerID returns the PeerID of the peer at the other end of the stream.
+func (c *lp2pStreamConn) RemotePeerID() peer.ID {
+	return c.Conn().RemotePeer()
+}
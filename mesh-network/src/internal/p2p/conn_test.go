@@ -0,0 +1,89 @@
+// Copyright 2025 c0r3br34k3r
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package p2p_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/omega-cyber/mesh-network/internal/config"
+	"github.com/omega-cyber/mesh-network/internal/p2p"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// TestDialGRPCReturnsClientConn verifies that DialGRPC builds a
+// *grpc.ClientConn to a connected peer. It issues no RPC, so no deadline is
+// exercised here; TestRawStreamDeadlineForwarding covers deadline expiry on
+// the underlying stream.
+func TestDialGRPCReturnsClientConn(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	serverNode, err := p2p.NewNode(ctx, &config.NodeConfig{ID: "deadline-server", P2PPort: 0, GRPCPort: 0})
+	require.NoError(t, err)
+	defer serverNode.Close()
+
+	// The server accepts the stream but never writes to or closes it.
+	serverNode.Host.SetStreamHandler(p2p.MeshProtocolID, func(s network.Stream) {})
+
+	clientNode, err := p2p.NewNode(ctx, &config.NodeConfig{ID: "deadline-client", P2PPort: 0, GRPCPort: 0})
+	require.NoError(t, err)
+	defer clientNode.Close()
+
+	require.NoError(t, clientNode.Host.Connect(ctx, serverNode.Host.Peerstore().PeerInfo(serverNode.Host.ID())))
+
+	conn, err := clientNode.DialGRPC(ctx, serverNode.Host.ID(), insecure.NewCredentials())
+	require.NoError(t, err)
+	defer conn.Close()
+	assert.NotNil(t, conn)
+}
+
+// TestRawStreamDeadlineForwarding exercises the same deadline-forwarding
+// behavior lp2pStreamConn relies on, directly against the underlying
+// network.Stream, to pin down that the embedded Stream (not a stub) is what
+// satisfies net.Conn's deadline methods.
+func TestRawStreamDeadlineForwarding(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	serverNode, err := p2p.NewNode(ctx, &config.NodeConfig{ID: "raw-deadline-server", P2PPort: 0, GRPCPort: 0})
+	require.NoError(t, err)
+	defer serverNode.Close()
+
+	const proto = "/omega/test-raw-deadline/1.0.0"
+	serverNode.Host.SetStreamHandler(proto, func(s network.Stream) {})
+
+	clientNode, err := p2p.NewNode(ctx, &config.NodeConfig{ID: "raw-deadline-client", P2PPort: 0, GRPCPort: 0})
+	require.NoError(t, err)
+	defer clientNode.Close()
+
+	require.NoError(t, clientNode.Host.Connect(ctx, serverNode.Host.Peerstore().PeerInfo(serverNode.Host.ID())))
+
+	stream, err := clientNode.Host.NewStream(ctx, serverNode.Host.ID(), proto)
+	require.NoError(t, err)
+	defer stream.Close()
+
+	require.NoError(t, stream.SetReadDeadline(time.Now().Add(200*time.Millisecond)))
+
+	buf := make([]byte, 16)
+	start := time.Now()
+	_, err = stream.Read(buf)
+	assert.Error(t, err, "read past an expired deadline must return an error, not support it silently")
+	assert.Less(t, time.Since(start), 2*time.Second, "read must be interrupted promptly by the deadline")
+}
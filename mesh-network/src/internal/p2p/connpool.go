@@ -0,0 +1,126 @@
+// Copyright 2025 c0r3br34k3r
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package p2p
+
+import (
+	"context"
+	"sync"
+
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+)
+
+// ConnPool keeps at most one *grpc.ClientConn per remote PeerID, reusing it
+// across RPCs instead of paying libp2p stream setup cost every call. Each
+// pooled ClientConn multiplexes RPCs over libp2p streams under
+// MeshProtocolID, opening new streams on demand (see DialGRPC's dialer) as
+// gRPC reconnects its transport. Pooled connections are invalidated as soon
+// as libp2p reports the underlying connection gone.
+type ConnPool struct {
+	node  *Node
+	creds credentials.TransportCredentials
+
+	mu    sync.Mutex
+	conns map[peer.ID]*grpc.ClientConn
+}
+
+// NewConnPool creates a ConnPool for node and registers a libp2p network
+// notifiee that evicts a peer's pooled connection as soon as it disconnects.
+func NewConnPool(node *Node, creds credentials.TransportCredentials) *ConnPool {
+	p := &ConnPool{
+		node:  node,
+		creds: creds,
+		conns: make(map[peer.ID]*grpc.ClientConn),
+	}
+	node.Host.Network().Notify(&connPoolNotifiee{pool: p})
+	return p
+}
+
+// Get returns the pooled *grpc.ClientConn for peerID, dialing one if none
+// exists yet or the existing one is no longer usable.
+func (p *ConnPool) Get(ctx context.Context, peerID peer.ID, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if conn, ok := p.conns[peerID]; ok {
+		if conn.GetState() != connectivity.Shutdown {
+			return conn, nil
+		}
+		delete(p.conns, peerID)
+	}
+
+	conn, err := p.node.DialGRPC(ctx, peerID, p.creds, opts...)
+	if err != nil {
+		return nil, err
+	}
+	p.conns[peerID] = conn
+	return conn, nil
+}
+
+// evict closes and removes the pooled connection for peerID, if any.
+func (p *ConnPool) evict(peerID peer.ID) {
+	p.mu.Lock()
+	conn, ok := p.conns[peerID]
+	if ok {
+		delete(p.conns, peerID)
+	}
+	p.mu.Unlock()
+
+	if ok {
+		logrus.Debugf("P2P: evicting pooled gRPC connection to %s after libp2p disconnect", peerID.Pretty())
+		if err := conn.Close(); err != nil {
+			logrus.WithError(err).Warnf("P2P: failed to close evicted gRPC connection to %s", peerID.Pretty())
+		}
+	}
+}
+
+// Close closes every pooled connection.
+func (p *ConnPool) Close() error {
+	p.mu.Lock()
+	conns := p.conns
+	p.conns = make(map[peer.ID]*grpc.ClientConn)
+	p.mu.Unlock()
+
+	var firstErr error
+	for _, conn := range conns {
+		if err := conn.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// connPoolNotifiee implements network.Notifiee, evicting a peer's pooled
+// connection as soon as libp2p reports it fully disconnected (i.e. no
+// connections to that peer remain).
+type connPoolNotifiee struct {
+	pool *ConnPool
+}
+
+func (n *connPoolNotifiee) Listen(network.Network, multiaddr.Multiaddr)      {}
+func (n *connPoolNotifiee) ListenClose(network.Network, multiaddr.Multiaddr) {}
+func (n *connPoolNotifiee) Connected(network.Network, network.Conn)         {}
+
+func (n *connPoolNotifiee) Disconnected(net network.Network, conn network.Conn) {
+	peerID := conn.RemotePeer()
+	if len(net.ConnsToPeer(peerID)) == 0 {
+		n.pool.evict(peerID)
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2025 c0r3br34k3r
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package p2p_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/omega-cyber/mesh-network/internal/config"
+	"github.com/omega-cyber/mesh-network/internal/p2p"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// TestConnPoolReusesConnectionPerPeer verifies that two Get calls for the
+// same peer return the identical *grpc.ClientConn instead of dialing twice.
+func TestConnPoolReusesConnectionPerPeer(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	serverNode, err := p2p.NewNode(ctx, &config.NodeConfig{ID: "pool-server", P2PPort: 0, GRPCPort: 0})
+	require.NoError(t, err)
+	defer serverNode.Close()
+	serverNode.Host.SetStreamHandler(p2p.MeshProtocolID, func(s network.Stream) {})
+
+	clientNode, err := p2p.NewNode(ctx, &config.NodeConfig{ID: "pool-client", P2PPort: 0, GRPCPort: 0})
+	require.NoError(t, err)
+	defer clientNode.Close()
+	require.NoError(t, clientNode.Host.Connect(ctx, serverNode.Host.Peerstore().PeerInfo(serverNode.Host.ID())))
+
+	pool := clientNode.ConnPool(insecure.NewCredentials())
+	defer pool.Close()
+
+	conn1, err := pool.Get(ctx, serverNode.Host.ID())
+	require.NoError(t, err)
+
+	conn2, err := pool.Get(ctx, serverNode.Host.ID())
+	require.NoError(t, err)
+
+	assert.Same(t, conn1, conn2, "ConnPool.Get must return the same *grpc.ClientConn for a peer it already dialed")
+}
+
+// TestConnPoolEvictsOnDisconnect verifies that disconnecting from a peer
+// removes its pooled connection, so the next Get dials a fresh one.
+func TestConnPoolEvictsOnDisconnect(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	serverNode, err := p2p.NewNode(ctx, &config.NodeConfig{ID: "evict-server", P2PPort: 0, GRPCPort: 0})
+	require.NoError(t, err)
+	defer serverNode.Close()
+	serverNode.Host.SetStreamHandler(p2p.MeshProtocolID, func(s network.Stream) {})
+
+	clientNode, err := p2p.NewNode(ctx, &config.NodeConfig{ID: "evict-client", P2PPort: 0, GRPCPort: 0})
+	require.NoError(t, err)
+	defer clientNode.Close()
+	require.NoError(t, clientNode.Host.Connect(ctx, serverNode.Host.Peerstore().PeerInfo(serverNode.Host.ID())))
+
+	pool := clientNode.ConnPool(insecure.NewCredentials())
+	defer pool.Close()
+
+	conn1, err := pool.Get(ctx, serverNode.Host.ID())
+	require.NoError(t, err)
+
+	require.NoError(t, clientNode.Host.Network().ClosePeer(serverNode.Host.ID()))
+	time.Sleep(200 * time.Millisecond)
+
+	require.NoError(t, clientNode.Host.Connect(ctx, serverNode.Host.Peerstore().PeerInfo(serverNode.Host.ID())))
+	conn2, err := pool.Get(ctx, serverNode.Host.ID())
+	require.NoError(t, err)
+
+	assert.NotSame(t, conn1, conn2, "ConnPool must dial a fresh connection after the peer disconnected")
+}
+
+// TestConnPoolConnectionServesRealRPC verifies that a pooled connection can
+// actually carry a gRPC call end to end, not merely build a *grpc.ClientConn.
+// DialGRPC previously dialed grpc.NewClient("", ...): that call succeeds
+// without error, but every real RPC through the resulting ClientConn then
+// fails at invoke time with "dns resolver: missing address", since an empty
+// target is never resolved. A test that only inspects the ClientConn's
+// identity (as the two tests above do) can't catch that.
+func TestConnPoolConnectionServesRealRPC(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	serverNode, err := p2p.NewNode(ctx, &config.NodeConfig{ID: "rpc-server", P2PPort: 0, GRPCPort: 0})
+	require.NoError(t, err)
+	defer serverNode.Close()
+
+	grpcServer := grpc.NewServer()
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	serverNode.Host.SetStreamHandler(p2p.MeshProtocolID, serverNode.WrapGRPC(grpcServer))
+
+	clientNode, err := p2p.NewNode(ctx, &config.NodeConfig{ID: "rpc-client", P2PPort: 0, GRPCPort: 0})
+	require.NoError(t, err)
+	defer clientNode.Close()
+	require.NoError(t, clientNode.Host.Connect(ctx, serverNode.Host.Peerstore().PeerInfo(serverNode.Host.ID())))
+
+	pool := clientNode.ConnPool(insecure.NewCredentials())
+	defer pool.Close()
+
+	conn, err := pool.Get(ctx, serverNode.Host.ID())
+	require.NoError(t, err)
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+}
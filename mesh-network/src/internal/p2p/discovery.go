@@ -0,0 +1,91 @@
+// Copyright 2025 c0r3br34k3r
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package p2p
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/discovery"
+	"github.com/libp2p/go-libp2p-core/peer"
+	discoveryrouting "github.com/libp2p/go-libp2p-discovery"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultAdvertiseTTL is the TTL passed to the routing discovery provider
+// records; re-advertising happens at half this interval so the record never
+// lapses between renewals.
+const defaultAdvertiseTTL = 1 * time.Hour
+
+// Advertise publishes this node as a provider of rendezvous on the DHT, and
+// keeps re-advertising every ttl/2 until ctx is canceled.
+func (n *Node) Advertise(ctx context.Context, rendezvous string) error {
+	if n.discovery == nil {
+		n.discovery = discoveryrouting.NewRoutingDiscovery(n.dht)
+	}
+
+	ttl, err := n.discovery.Advertise(ctx, rendezvous, discovery.TTL(defaultAdvertiseTTL))
+	if err != nil {
+		return fmt.Errorf("failed to advertise rendezvous %q: %w", rendezvous, err)
+	}
+	if ttl <= 0 {
+		ttl = defaultAdvertiseTTL
+	}
+
+	go func() {
+		ticker := time.NewTicker(ttl / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := n.discovery.Advertise(ctx, rendezvous, discovery.TTL(defaultAdvertiseTTL)); err != nil {
+					logrus.WithError(err).Warnf("P2P: failed to re-advertise rendezvous %q", rendezvous)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// FindPeers searches the DHT for peers advertising rendezvous and streams
+// them back as they're discovered. The channel is closed once the
+// underlying search completes or ctx is canceled.
+func (n *Node) FindPeers(ctx context.Context, rendezvous string) (<-chan peer.AddrInfo, error) {
+	if n.discovery == nil {
+		n.discovery = discoveryrouting.NewRoutingDiscovery(n.dht)
+	}
+
+	peerChan, err := n.discovery.FindPeers(ctx, rendezvous)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find peers for rendezvous %q: %w", rendezvous, err)
+	}
+	return peerChan, nil
+}
+
+// advertiseRoles advertises every role in n.cfg.Roles, logging (but not
+// failing Bootstrap on) any individual advertise error.
+func (n *Node) advertiseRoles(ctx context.Context) {
+	for _, role := range n.cfg.Roles {
+		if err := n.Advertise(ctx, role); err != nil {
+			logrus.WithError(err).Warnf("P2P: failed to advertise role %q", role)
+			continue
+		}
+		logrus.Infof("P2P: advertising role %q on the DHT", role)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2025 c0r3br34k3r
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package p2p_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/omega-cyber/mesh-network/internal/config"
+	"github.com/omega-cyber/mesh-network/internal/p2p"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFindPeersDiscoversAdvertisedRole verifies that a node advertising a
+// rendezvous role is discoverable by another node that only knows the role
+// name, via the shared Kad-DHT.
+func TestFindPeersDiscoversAdvertisedRole(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	bootstrapNode, err := p2p.NewNode(ctx, &config.NodeConfig{ID: "bootstrap-node", P2PPort: 0, GRPCPort: 0})
+	require.NoError(t, err)
+	defer bootstrapNode.Close()
+	require.NoError(t, bootstrapNode.Bootstrap(ctx))
+
+	bootstrapAddr := fmt.Sprintf("%s/p2p/%s", bootstrapNode.Host.Addrs()[0].String(), bootstrapNode.Host.ID().Pretty())
+
+	sensorCfg := &config.NodeConfig{
+		ID:            "sensor-node",
+		P2PPort:       0,
+		GRPCPort:      0,
+		JoinAddresses: []string{bootstrapAddr},
+		Roles:         []string{"sensor"},
+	}
+	sensorNode, err := p2p.NewNode(ctx, sensorCfg)
+	require.NoError(t, err)
+	defer sensorNode.Close()
+	require.NoError(t, sensorNode.Bootstrap(ctx))
+
+	seekerCfg := &config.NodeConfig{
+		ID:            "seeker-node",
+		P2PPort:       0,
+		GRPCPort:      0,
+		JoinAddresses: []string{bootstrapAddr},
+	}
+	seekerNode, err := p2p.NewNode(ctx, seekerCfg)
+	require.NoError(t, err)
+	defer seekerNode.Close()
+	require.NoError(t, seekerNode.Bootstrap(ctx))
+
+	found, err := seekerNode.FindPeers(ctx, "sensor")
+	require.NoError(t, err)
+
+	deadline := time.After(15 * time.Second)
+	for {
+		select {
+		case addrInfo, ok := <-found:
+			if !ok {
+				t.Fatal("FindPeers channel closed before the sensor node was discovered")
+			}
+			if addrInfo.ID == sensorNode.Host.ID() {
+				assert.Equal(t, sensorNode.Host.ID(), addrInfo.ID)
+				return
+			}
+		case <-deadline:
+			t.Fatal("timed out waiting to discover the sensor role peer")
+		}
+	}
+}
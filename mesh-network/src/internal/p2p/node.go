@@ -22,36 +22,116 @@ import (
 	"sync"
 	"time"
 
+	"github.com/omega-cyber/mesh-network/internal/addrbook"
 	"github.com/omega-cyber/mesh-network/internal/config"
+	"github.com/omega-cyber/mesh-network/internal/identity"
+	"github.com/omega-cyber/mesh-network/internal/nat"
 	"github.com/sirupsen/logrus"
 
 	"github.com/libp2p/go-libp2p"
-	"github.com/libp2p/go-libp2p-core/crypto"
+	connmgr "github.com/libp2p/go-libp2p-connmgr"
 	"github.com/libp2p/go-libp2p-core/host"
 	"github.com/libp2p/go-libp2p-core/network"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/libp2p/go-libp2p-core/routing"
+	discoveryrouting "github.com/libp2p/go-libp2p-discovery"
 	dht "github.com/libp2p/go-libp2p-kad-dht"
 	quic "github.com/libp2p/go-libp2p-quic-transport"
 	"github.com/multiformats/go-multiaddr"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/credentials"
 )
 
+// MeshProtocolID is the libp2p stream protocol the mesh gRPC service is
+// served on. It lives here (rather than in internal/grpc) so this package's
+// own DialGRPC/WrapGRPC can reference it without an import cycle.
+const MeshProtocolID = "/omega/mesh/1.0.0"
+
+// peerGRPCTarget is the placeholder target DialGRPC dials with. It is never
+// resolved: the grpc.WithContextDialer passed alongside it always opens a
+// libp2p stream to the requested peerID regardless of this string, but
+// grpc.DialContext still requires a non-empty target to avoid engaging a
+// real resolver.
+const peerGRPCTarget = "p2p-peer"
+
 // Node represents a libp2p node in the Omega mesh network.
 type Node struct {
-	Host host.Host
-	dht  *dht.IpfsDHT
-	cfg  *config.NodeConfig
+	Host       host.Host
+	dht        *dht.IpfsDHT
+	cfg        *config.NodeConfig
+	pubsub     *pubsubHub
+	discovery  *discoveryrouting.RoutingDiscovery
+	addrBook   *addrbook.AddressBook
+	natMappers []*nat.Mapper
+}
+
+// natMappingLease is how long a UPnP/NAT-PMP port mapping is requested for
+// before it needs renewing; NAT-enabled nodes renew it at half this
+// interval for as long as they're running.
+const natMappingLease = 30 * time.Minute
+
+// AddressBook returns the node's peer address book, seeded from
+// NodeConfig.JoinAddresses and persisted at NodeConfig.AddrBookPath (if
+// set), so subsystems like a PEX exchange handler can read and extend it.
+func (n *Node) AddressBook() *addrbook.AddressBook {
+	return n.addrBook
+}
+
+// TrustedPeers returns the node's configured TrustedPeers allow-list, so
+// other subsystems (e.g. the gRPC auth interceptor) can enforce it without
+// reaching into NodeConfig directly.
+func (n *Node) TrustedPeers() []config.TrustedPeer {
+	return n.cfg.TrustedPeers
+}
+
+// ConnPool returns a new ConnPool bound to this node, using creds for every
+// connection it dials. Callers that talk to many peers (or the same peer
+// repeatedly) should keep and reuse one pool rather than calling DialGRPC
+// directly each time.
+func (n *Node) ConnPool(creds credentials.TransportCredentials) *ConnPool {
+	return NewConnPool(n, creds)
 }
 
 // NewNode creates and initializes a new libp2p node.
 func NewNode(ctx context.Context, cfg *config.NodeConfig) (*Node, error) {
-	// Generate a new key pair for this node.
-	// In a real application, this would be loaded from a secure store.
-	priv, _, err := crypto.GenerateKeyPair(crypto.Ed25519, -1)
+	// Load the node's persistent identity so its PeerID (and DHT routing
+	// identity) survives restarts instead of being regenerated each time.
+	priv, err := identity.LoadOrCreate(cfg.IdentityPath, cfg.IdentityPassphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load node identity: %w", err)
+	}
+
+	lowWater, highWater, gracePeriod := cfg.ConnMgrLowWater, cfg.ConnMgrHighWater, cfg.ConnMgrGracePeriod
+	if lowWater == 0 {
+		lowWater = config.DefaultConnMgrLowWater
+	}
+	if highWater == 0 {
+		highWater = config.DefaultConnMgrHighWater
+	}
+	if gracePeriod == 0 {
+		gracePeriod = config.DefaultConnMgrGracePeriod
+	}
+	cm, err := connmgr.NewConnManager(
+		lowWater,
+		highWater,
+		connmgr.WithGracePeriod(gracePeriod),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create connection manager: %w", err)
+	}
+
+	filters, err := buildAddrFilters(cfg.AddrFilters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse addr filters: %w", err)
+	}
+
+	announceAddrs, err := parseMultiaddrs(cfg.AnnounceAddrs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse announce addrs: %w", err)
+	}
+	noAnnounceAddrs, err := parseMultiaddrs(cfg.NoAnnounceAddrs)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate key pair: %w", err)
+		return nil, fmt.Errorf("failed to parse no-announce addrs: %w", err)
 	}
 
 	// Create the libp2p host.
@@ -73,8 +153,12 @@ func NewNode(ctx context.Context, cfg *config.NodeConfig) (*Node, error) {
 			kadDHT, err := dht.New(ctx, h)
 			return kadDHT, err
 		}),
-		// Let's prevent our peer from having too many open connections.
-		libp2p.ConnectionManager(nil), // TODO: Configure this properly
+		// Trim connections back towards LowWater once HighWater is reached.
+		libp2p.ConnectionManager(cm),
+		// Never dial or advertise addresses matching AddrFilters.
+		libp2p.FilterAddresses(filters...),
+		// Override/trim the addresses we announce to peers and the DHT.
+		libp2p.AddrsFactory(addrsFactory(announceAddrs, noAnnounceAddrs)),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create libp2p host: %w", err)
@@ -93,9 +177,63 @@ func NewNode(ctx context.Context, cfg *config.NodeConfig) (*Node, error) {
 		logrus.Infof("Listening on: %s", addr.String())
 	}
 
+	if err := node.initPubSub(ctx, cfg.PubSub); err != nil {
+		h.Close()
+		return nil, fmt.Errorf("failed to initialize pubsub: %w", err)
+	}
+
+	node.addrBook = addrbook.New(cfg.AddrBookPath, cfg.AddrBookStrict)
+	if cfg.AddrBookPath != "" {
+		if err := node.addrBook.Load(); err != nil {
+			logrus.WithError(err).Warn("Failed to load address book, starting empty")
+		}
+	}
+	node.addrBook.Seed(cfg.JoinAddresses)
+
+	if cfg.NATEnabled {
+		node.mapExternalPort(ctx)
+	}
+
 	return node, nil
 }
 
+// mapExternalPort discovers a UPnP/NAT-PMP gateway and maps P2PPort for both
+// TCP and UDP (the QUIC transport listens on UDP, but the mapping is
+// requested for both so a TCP fallback transport would also be reachable)
+// so the node can accept inbound connections from behind a NAT, then adds
+// the resulting public multiaddr to the address book so it can be
+// advertised to peers. Failure is logged, not fatal: the node still works
+// for outbound connections and DHT-relayed discovery without it.
+func (n *Node) mapExternalPort(ctx context.Context) {
+	var externalIP net.IP
+	for _, proto := range []string{"udp", "tcp"} {
+		mapper, err := nat.Map(ctx, n.cfg.P2PPort, n.cfg.P2PPort, proto, natMappingLease)
+		if err != nil {
+			logrus.WithError(err).Warnf("NAT %s port mapping failed, node may be unreachable from outside its local network", proto)
+			continue
+		}
+		n.natMappers = append(n.natMappers, mapper)
+		if externalIP == nil {
+			externalIP = mapper.ExternalIP()
+		}
+	}
+	if len(n.natMappers) == 0 {
+		return
+	}
+	if externalIP == nil {
+		logrus.Warn("NAT gateway mapped our port but did not report an external IP")
+		return
+	}
+
+	publicAddr := fmt.Sprintf("/ip4/%s/udp/%d/quic", externalIP.String(), n.cfg.P2PPort)
+	if _, err := multiaddr.NewMultiaddr(publicAddr); err != nil {
+		logrus.WithError(err).Warnf("NAT gateway reported an unusable external address %q", externalIP)
+		return
+	}
+	_ = n.addrBook.Add(fmt.Sprintf("%s/p2p/%s", publicAddr, n.Host.ID().Pretty()), "")
+	logrus.Infof("NAT port mapping active, advertising public address: %s", publicAddr)
+}
+
 // Bootstrap connects to a set of bootstrap peers and initializes the DHT.
 func (n *Node) Bootstrap(ctx context.Context) error {
 	var wg sync.WaitGroup
@@ -111,17 +249,47 @@ func (n *Node) Bootstrap(ctx context.Context) error {
 		}
 	}
 
+	trustedAddrs, err := parseTrustedPeers(n.cfg.TrustedPeers)
+	if err != nil {
+		return fmt.Errorf("failed to parse trusted peers: %w", err)
+	}
+
 	logrus.Info("Bootstrapping DHT...")
 	for _, p := range bootstrapPeers {
+		if expected, pinned := trustedAddrs[p.ID]; pinned && !multiaddrsOverlap(expected, p.Addrs) {
+			logrus.Warnf("Refusing to connect to %s: observed multiaddrs do not match pinned TrustedPeers entry", p.ID.Pretty())
+			continue
+		}
+		addrPinnedToOtherPeer := false
+		for id, expected := range trustedAddrs {
+			if id != p.ID && multiaddrsOverlap(expected, p.Addrs) {
+				addrPinnedToOtherPeer = true
+				break
+			}
+		}
+		if addrPinnedToOtherPeer {
+			logrus.Warnf("Refusing to connect to %s: its multiaddrs are pinned to a different PeerID in TrustedPeers", p.ID.Pretty())
+			continue
+		}
 		wg.Add(1)
 		go func(p peer.AddrInfo) {
 			defer wg.Done()
 			logrus.Infof("Connecting to bootstrap peer: %s", p.ID.Pretty())
+			addrs, addrErr := peer.AddrInfoToP2pAddrs(&p)
+			for _, a := range addrs {
+				_ = n.addrBook.Add(a.String(), "")
+				n.addrBook.MarkAttempt(a.String())
+			}
 			if err := n.Host.Connect(ctx, p); err != nil {
 				logrus.WithError(err).Warnf("Failed to connect to bootstrap peer: %s", p.ID.Pretty())
 			} else {
 				logrus.Infof("Connected to bootstrap peer: %s", p.ID.Pretty())
 				connectedPeers++
+				if addrErr == nil {
+					for _, a := range addrs {
+						n.addrBook.MarkGood(a.String())
+					}
+				}
 			}
 		}(p)
 	}
@@ -136,6 +304,10 @@ func (n *Node) Bootstrap(ctx context.Context) error {
 		return fmt.Errorf("failed to bootstrap DHT: %w", err)
 	}
 
+	// Advertise our configured roles so peers can find us via FindPeers
+	// without already knowing our PeerID.
+	n.advertiseRoles(ctx)
+
 	// Periodically print routing table stats.
 	go func() {
 		ticker := time.NewTicker(1 * time.Minute)
@@ -156,64 +328,25 @@ func (n *Node) Bootstrap(ctx context.Context) error {
 // Close gracefully shuts down the libp2p node.
 func (n *Node) Close() error {
 	logrus.Info("Closing P2P Node...")
+	for _, mapper := range n.natMappers {
+		if err := mapper.Unmap(context.Background()); err != nil {
+			logrus.WithError(err).Warn("Failed to remove NAT port mapping")
+		}
+	}
+	if n.cfg.AddrBookPath != "" {
+		if err := n.addrBook.Save(); err != nil {
+			logrus.WithError(err).Warn("Failed to save address book")
+		}
+	}
 	return n.Host.Close()
 }
 
 // --- Helper for gRPC over libp2p ---
 
-// lp2pStreamConn is a net.Conn implementation that wraps a libp2p stream.
-type lp2pStreamConn struct {
-	network.Stream
-}
-
-// Read reads data from the libp2p stream.
-func (c *lp2pStreamConn) Read(b []byte) (n int, err error) {
-	return c.Stream.Read(b)
-}
-
-// Write writes data to the libp2p stream.
-func (c *lp2pStreamConn) Write(b []byte) (n int, err error) {
-	return c.Stream.Write(b)
-}
-
-// Close closes the libp2p stream.
-func (c *lp2pStreamConn) Close() error {
-	return c.Stream.Close()
-}
-
-// LocalAddr returns the local Multiaddr.
-func (c *lp2pStreamConn) LocalAddr() net.Addr {
-	return &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 0} // Placeholder
-}
-
-// RemoteAddr returns the remote Multiaddr.
-func (c *lp2pStreamConn) RemoteAddr() net.Addr {
-	return &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 0} // Placeholder
-}
-
-// SetDeadline is not implemented for libp2p streams.
-func (c *lp2pStreamConn) SetDeadline(t time.Time) error {
-	return fmt.Errorf("SetDeadline not supported for libp2p streams")
-}
-
-// SetReadDeadline is not implemented for libp2p streams.
-func (c *lp2pStreamConn) SetReadDeadline(t time.Time) error {
-	return fmt.Errorf("SetReadDeadline not supported for libp2p streams")
-}
-
-// SetWriteDeadline is not implemented for libp2p streams.
-func (c *lp2pStreamConn) SetWriteDeadline(t time.Time) error {
-	return fmt.Errorf("SetWriteDeadline not supported for libp2p streams")
-}
-
 // WrapGRPC returns a stream handler function that can serve a gRPC server on a libp2p stream.
 func (n *Node) WrapGRPC(grpcServer *grpc.Server) network.StreamHandler {
 	return func(s network.Stream) {
 		logrus.Debugf("Received new gRPC stream from %s, protocol %s", s.Conn().RemotePeer().Pretty(), s.Protocol())
-		
-		// Create a context for the gRPC stream.
-		// Attach the remote peer ID to the context so gRPC services can access it.
-		ctx := context.WithValue(s.Context(), "peerid", s.Conn().RemotePeer().String())
 
 		// Serve the gRPC server on the libp2p stream wrapped as a net.Conn.
 		go func() {
@@ -255,6 +388,102 @@ func (l *singleUseListener) Addr() net.Addr {
 	return &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 0}
 }
 
+// buildAddrFilters parses NodeConfig.AddrFilters CIDR-like multiaddr masks
+// (e.g. "/ip4/10.0.0.0/ipcidr/8") into the *net.IPNet list libp2p.FilterAddresses
+// expects; any matching address is never dialed or advertised.
+func buildAddrFilters(masks []string) ([]*net.IPNet, error) {
+	filters := make([]*net.IPNet, 0, len(masks))
+	for _, mask := range masks {
+		maddr, err := multiaddr.NewMultiaddr(mask)
+		if err != nil {
+			return nil, fmt.Errorf("invalid addr filter %q: %w", mask, err)
+		}
+		ipNet, err := multiaddr.ToIPNet(maddr)
+		if err != nil {
+			return nil, fmt.Errorf("addr filter %q is not a CIDR mask: %w", mask, err)
+		}
+		filters = append(filters, ipNet)
+	}
+	return filters, nil
+}
+
+func parseMultiaddrs(addrs []string) ([]multiaddr.Multiaddr, error) {
+	if len(addrs) == 0 {
+		return nil, nil
+	}
+	out := make([]multiaddr.Multiaddr, 0, len(addrs))
+	for _, a := range addrs {
+		maddr, err := multiaddr.NewMultiaddr(a)
+		if err != nil {
+			return nil, fmt.Errorf("invalid multiaddr %q: %w", a, err)
+		}
+		out = append(out, maddr)
+	}
+	return out, nil
+}
+
+// addrsFactory returns a libp2p AddrsFactory that overrides the advertised
+// address set with announce (if non-empty) and always strips noAnnounce.
+func addrsFactory(announce, noAnnounce []multiaddr.Multiaddr) func([]multiaddr.Multiaddr) []multiaddr.Multiaddr {
+	return func(listen []multiaddr.Multiaddr) []multiaddr.Multiaddr {
+		base := listen
+		if len(announce) > 0 {
+			base = announce
+		}
+		if len(noAnnounce) == 0 {
+			return base
+		}
+		out := make([]multiaddr.Multiaddr, 0, len(base))
+		for _, addr := range base {
+			hidden := false
+			for _, hide := range noAnnounce {
+				if addr.Equal(hide) {
+					hidden = true
+					break
+				}
+			}
+			if !hidden {
+				out = append(out, addr)
+			}
+		}
+		return out
+	}
+}
+
+// parseTrustedPeers resolves a NodeConfig.TrustedPeers allow-list into a map
+// of pinned PeerID to the multiaddrs it is expected to be reachable at.
+func parseTrustedPeers(trusted []config.TrustedPeer) (map[peer.ID][]multiaddr.Multiaddr, error) {
+	if len(trusted) == 0 {
+		return nil, nil
+	}
+	pinned := make(map[peer.ID][]multiaddr.Multiaddr, len(trusted))
+	for _, tp := range trusted {
+		id, err := peer.Decode(tp.PeerID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted peer id %q: %w", tp.PeerID, err)
+		}
+		addr, err := multiaddr.NewMultiaddr(tp.Multiaddr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted peer multiaddr %q: %w", tp.Multiaddr, err)
+		}
+		pinned[id] = append(pinned[id], addr)
+	}
+	return pinned, nil
+}
+
+// multiaddrsOverlap reports whether any address in observed matches one of
+// the pinned expected multiaddrs for a trusted peer.
+func multiaddrsOverlap(expected, observed []multiaddr.Multiaddr) bool {
+	for _, e := range expected {
+		for _, o := range observed {
+			if e.Equal(o) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func parsePeers(addrs []string) ([]peer.AddrInfo, error) {
 	var peers []peer.AddrInfo
 	for _, addrStr := range addrs {
@@ -273,22 +502,35 @@ func parsePeers(addrs []string) ([]peer.AddrInfo, error) {
 
 // --- Client for gRPC over libp2p ---
 
-// DialGRPC creates a gRPC client connection to a remote libp2p peer.
-func (n *Node) DialGRPC(ctx context.Context, peerID peer.ID) (*grpc.ClientConn, error) {
-	// Create a new stream to the remote peer using the gRPC protocol ID.
-	stream, err := n.Host.NewStream(ctx, peerID, MeshProtocolID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open libp2p stream to peer %s: %w", peerID.Pretty(), err)
-	}
-
-	// Use grpc.NewClient with a custom DialOption that uses our stream.
-	conn := &lp2pStreamConn{Stream: stream}
-	return grpc.NewClient(
-		"", // Target is ignored when using a custom DialOption
-		grpc.WithTransportCredentials(insecure.NewCredentials()), // Or secure credentials if using TLS
-		grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) {
-			// This dialer returns our already established libp2p stream as a net.Conn.
-			return conn, nil
+// DialGRPC returns a new *grpc.ClientConn to peerID whose dialer opens a
+// fresh libp2p stream each time gRPC's transport connects. Most callers
+// should prefer Node.ConnPool(), which keeps one reusable ClientConn per
+// peer instead of dialing a new one for every call; DialGRPC is the low-level primitive the pool (and
+// anyone who genuinely wants a one-off connection) builds on. creds is
+// typically internal/grpc's libp2p-identity-derived transport credentials;
+// it isn't constructed here to avoid an import cycle between this package
+// and internal/grpc. Extra dial options are appended after the required
+// credentials and dialer options, mirroring grpc.DialContext's composition
+// style.
+func (n *Node) DialGRPC(ctx context.Context, peerID peer.ID, creds credentials.TransportCredentials, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	dialOpts := append([]grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			// Open a fresh libp2p stream every time the gRPC transport needs
+			// one, so automatic reconnects after a dropped connection work
+			// instead of failing the way a single-use stream/listener would.
+			stream, err := n.Host.NewStream(ctx, peerID, MeshProtocolID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open libp2p stream to peer %s: %w", peerID.Pretty(), err)
+			}
+			return &lp2pStreamConn{Stream: stream}, nil
 		}),
-	), nil
+	}, opts...)
+
+	// The target string is ignored by the custom dialer above (it always
+	// opens a stream to peerID); it only needs to be a non-empty value gRPC
+	// accepts without invoking a resolver. grpc.NewClient defers resolution
+	// until the first RPC and would fail every call against an empty target
+	// with "dns resolver: missing address", so use DialContext instead.
+	return grpc.DialContext(ctx, peerGRPCTarget, dialOpts...)
 }
@@ -99,3 +99,31 @@ func TestNodeBootstrap(t *testing.T) {
 	assert.GreaterOrEqual(t, joinNode.Host.Routing().(*dht.IpfsDHT).RoutingTable().Size(), 1)
 	assert.GreaterOrEqual(t, bootstrapNode.Host.Routing().(*dht.IpfsDHT).RoutingTable().Size(), 1)
 }
+
+// TestNodeAddrFiltersAndAnnounceAddrs verifies that addresses matching
+// AddrFilters never show up in Host.Addrs(), and that AnnounceAddrs
+// overrides what the node advertises.
+func TestNodeAddrFiltersAndAnnounceAddrs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	announce := "/ip4/203.0.113.7/udp/7946/quic"
+	cfg := &config.NodeConfig{
+		ID:            "filtered-node",
+		P2PPort:       0,
+		GRPCPort:      0,
+		AddrFilters:   []string{"/ip4/127.0.0.0/ipcidr/8"},
+		AnnounceAddrs: []string{announce},
+	}
+
+	node, err := p2p.NewNode(ctx, cfg)
+	require.NoError(t, err)
+	defer node.Close()
+
+	for _, addr := range node.Host.Addrs() {
+		assert.NotContains(t, addr.String(), "127.0.0.1", "filtered loopback address leaked into Host.Addrs()")
+	}
+
+	require.Len(t, node.Host.Addrs(), 1)
+	assert.Equal(t, announce, node.Host.Addrs()[0].String())
+}
@@ -0,0 +1,187 @@
+// Copyright 2025 c0r3br34k3r
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package p2p
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/omega-cyber/mesh-network/internal/config"
+	"github.com/sirupsen/logrus"
+)
+
+// BlossomSubProtocolID is negotiated ahead of the standard GossipSub v1.1
+// protocol ID so that BlossomSub-aware peers (see EXTERNAL DOC 5) get the
+// wider message-ID domain separation it offers, while plain GossipSub peers
+// fall back transparently via multistream-select.
+const BlossomSubProtocolID protocol.ID = "/omega/blossomsub/1.0.0"
+
+// Message is a decoded PubSub message delivered to a Subscribe channel.
+type Message struct {
+	// From is the peer that originated the message.
+	From peer.ID
+	// Topic is the GossipSub topic the message was received on.
+	Topic string
+	// Data is the raw message payload.
+	Data []byte
+}
+
+// pubsubHub owns the GossipSub router and the set of topics/subscriptions
+// a Node has joined.
+type pubsubHub struct {
+	ps *pubsub.PubSub
+
+	mu     sync.Mutex
+	topics map[string]*pubsub.Topic
+}
+
+// initPubSub constructs the GossipSub router for the node, applying the
+// peer-scoring configuration from NodeConfig.PubSub. BlossomSub-style nodes
+// negotiate the same protocol ID family, so no separate router is needed;
+// WithGossipSubProtocols below advertises both protocol IDs and lets libp2p
+// multistream-select pick whichever the remote peer supports.
+func (n *Node) initPubSub(ctx context.Context, cfg config.PubSubConfig) error {
+	scoreParams := &pubsub.PeerScoreParams{
+		AppSpecificWeight: 1,
+		DecayInterval:     pubsub.DefaultDecayInterval,
+		DecayToZero:       pubsub.DefaultDecayToZero,
+		Topics:            map[string]*pubsub.TopicScoreParams{},
+	}
+	scoreThresholds := &pubsub.PeerScoreThresholds{
+		GossipThreshold:             cfg.GossipThreshold,
+		PublishThreshold:            cfg.PublishThreshold,
+		GraylistThreshold:           cfg.ScoreThreshold,
+		AcceptPXThreshold:           0,
+		OpportunisticGraftThreshold: 0,
+	}
+
+	for _, topic := range cfg.Topics {
+		scoreParams.Topics[topic] = &pubsub.TopicScoreParams{
+			TopicWeight:                     1,
+			InvalidMessageDeliveriesWeight:  -cfg.InvalidMessageDeliveries,
+			InvalidMessageDeliveriesDecay:   cfg.InvalidMessageDecay,
+			TimeInMeshWeight:                0.01,
+			TimeInMeshQuantum:               pubsub.DefaultDecayInterval,
+			TimeInMeshCap:                   10,
+			FirstMessageDeliveriesWeight:    1,
+			FirstMessageDeliveriesDecay:     cfg.InvalidMessageDecay,
+			FirstMessageDeliveriesCap:       50,
+			MeshMessageDeliveriesWeight:     0,
+			MeshMessageDeliveriesDecay:      cfg.InvalidMessageDecay,
+			MeshMessageDeliveriesThreshold:  1,
+			MeshMessageDeliveriesCap:        50,
+			MeshMessageDeliveriesActivation: 0,
+			MeshFailurePenaltyWeight:        0,
+			MeshFailurePenaltyDecay:         cfg.InvalidMessageDecay,
+		}
+	}
+
+	ps, err := pubsub.NewGossipSub(ctx, n.Host,
+		pubsub.WithPeerScore(scoreParams, scoreThresholds),
+		pubsub.WithGossipSubProtocols(
+			[]protocol.ID{BlossomSubProtocolID, pubsub.GossipSubID_v11},
+			pubsub.GossipSubDefaultFeatures,
+		),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create GossipSub router: %w", err)
+	}
+
+	n.pubsub = &pubsubHub{
+		ps:     ps,
+		topics: make(map[string]*pubsub.Topic),
+	}
+
+	for _, topic := range cfg.Topics {
+		if _, err := n.topic(topic); err != nil {
+			return fmt.Errorf("failed to join topic %q: %w", topic, err)
+		}
+		logrus.Infof("P2P: joined pubsub topic %s", topic)
+	}
+
+	return nil
+}
+
+// topic returns the joined pubsub.Topic handle for name, joining it on
+// first use.
+func (n *Node) topic(name string) (*pubsub.Topic, error) {
+	n.pubsub.mu.Lock()
+	defer n.pubsub.mu.Unlock()
+
+	if t, ok := n.pubsub.topics[name]; ok {
+		return t, nil
+	}
+	t, err := n.pubsub.ps.Join(name)
+	if err != nil {
+		return nil, err
+	}
+	n.pubsub.topics[name] = t
+	return t, nil
+}
+
+// Publish broadcasts msg to every subscriber of topic across the mesh.
+func (n *Node) Publish(ctx context.Context, topic string, msg []byte) error {
+	if n.pubsub == nil {
+		return fmt.Errorf("pubsub is not initialized on this node")
+	}
+	t, err := n.topic(topic)
+	if err != nil {
+		return fmt.Errorf("failed to join topic %q: %w", topic, err)
+	}
+	return t.Publish(ctx, msg)
+}
+
+// Subscribe joins topic (if not already joined) and returns a channel of
+// Messages delivered to it. The channel is closed when ctx is canceled.
+func (n *Node) Subscribe(ctx context.Context, topic string) (<-chan Message, error) {
+	if n.pubsub == nil {
+		return nil, fmt.Errorf("pubsub is not initialized on this node")
+	}
+	t, err := n.topic(topic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to join topic %q: %w", topic, err)
+	}
+
+	sub, err := t.Subscribe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to topic %q: %w", topic, err)
+	}
+
+	out := make(chan Message, 32)
+	go func() {
+		defer close(out)
+		defer sub.Cancel()
+		for {
+			m, err := sub.Next(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					logrus.WithError(err).Warnf("P2P: pubsub subscription to %s ended", topic)
+				}
+				return
+			}
+			select {
+			case out <- Message{From: peer.ID(m.Message.GetFrom()), Topic: topic, Data: m.Data}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
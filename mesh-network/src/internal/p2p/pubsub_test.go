@@ -0,0 +1,81 @@
+// Copyright 2025 c0r3br34k3r
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package p2p_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/omega-cyber/mesh-network/internal/config"
+	"github.com/omega-cyber/mesh-network/internal/p2p"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pubsubTestConfig(id string, join []string) *config.NodeConfig {
+	return &config.NodeConfig{
+		ID:            id,
+		P2PPort:       0,
+		GRPCPort:      0,
+		JoinAddresses: join,
+		PubSub: config.PubSubConfig{
+			Topics:                   []string{"omega/test/v1"},
+			ScoreThreshold:           -500,
+			GossipThreshold:          -100,
+			PublishThreshold:         -250,
+			InvalidMessageDeliveries: 1,
+			InvalidMessageDecay:      0.5,
+		},
+	}
+}
+
+// TestPubSubDeliveryToBootstrappedPeer verifies that a message published by
+// one node is delivered to a peer that joined it through JoinAddresses and
+// DHT bootstrap. The two nodes are directly connected, so delivery here is a
+// single hop.
+func TestPubSubDeliveryToBootstrappedPeer(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	bootstrapNode, err := p2p.NewNode(ctx, pubsubTestConfig("bootstrap-node", nil))
+	require.NoError(t, err)
+	defer bootstrapNode.Close()
+	require.NoError(t, bootstrapNode.Bootstrap(ctx))
+
+	bootstrapAddr := fmt.Sprintf("%s/p2p/%s", bootstrapNode.Host.Addrs()[0].String(), bootstrapNode.Host.ID().Pretty())
+
+	joinNode, err := p2p.NewNode(ctx, pubsubTestConfig("join-node", []string{bootstrapAddr}))
+	require.NoError(t, err)
+	defer joinNode.Close()
+	require.NoError(t, joinNode.Bootstrap(ctx))
+
+	// Give the gossipsub mesh time to form over the bootstrap connection.
+	time.Sleep(2 * time.Second)
+
+	sub, err := joinNode.Subscribe(ctx, "omega/test/v1")
+	require.NoError(t, err)
+
+	require.NoError(t, bootstrapNode.Publish(ctx, "omega/test/v1", []byte("hello mesh")))
+
+	select {
+	case msg := <-sub:
+		assert.Equal(t, "hello mesh", string(msg.Data))
+		assert.Equal(t, bootstrapNode.Host.ID(), msg.From)
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for pubsub message to arrive")
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 c0r3br34k3r
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package trustfabric provides the gRPC client dialer used to connect to
+// the Trust Fabric service, with a config-driven exponential backoff
+// reconnect loop instead of failing on the first dial error.
+package trustfabric
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/omega-cyber/mesh-network/internal/config"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// ClientCredentialsOption builds the grpc.DialOption Dial should be called
+// with, based on sec.TLSEnabled: TLS (via config.BuildClientTLS) or
+// insecure credentials.
+func ClientCredentialsOption(sec config.SecurityConfig) (grpc.DialOption, error) {
+	if !sec.TLSEnabled {
+		return grpc.WithTransportCredentials(insecure.NewCredentials()), nil
+	}
+
+	tlsCfg, err := config.BuildClientTLS(sec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build TrustFabric client TLS config: %w", err)
+	}
+	return grpc.WithTransportCredentials(credentials.NewTLS(tlsCfg)), nil
+}
+
+// ErrReconnectExhausted is returned by Dialer.Dial when the cumulative
+// elapsed retry time exceeds TrustFabricConfig.ReConnectTotalTimeThreshold
+// before a dial attempt succeeds.
+var ErrReconnectExhausted = errors.New("trustfabric: reconnect budget exhausted")
+
+// Dialer wraps grpc.DialContext with an exponential backoff reconnect loop,
+// bounded by the thresholds in TrustFabricConfig.
+type Dialer struct {
+	cfg config.TrustFabricConfig
+}
+
+// NewDialer creates a Dialer for cfg, filling in defaults for any zero
+// duration field.
+func NewDialer(cfg config.TrustFabricConfig) *Dialer {
+	if cfg.ReConnectBackoffThreshold == 0 {
+		cfg.ReConnectBackoffThreshold = config.DefaultReConnectBackoffThreshold
+	}
+	if cfg.ReConnectTotalTimeThreshold == 0 {
+		cfg.ReConnectTotalTimeThreshold = config.DefaultReConnectTotalTimeThreshold
+	}
+	if cfg.ConnectionTimeout == 0 {
+		cfg.ConnectionTimeout = config.DefaultConnectionTimeout
+	}
+	return &Dialer{cfg: cfg}
+}
+
+// Dial repeatedly attempts grpc.DialContext against cfg.GRPCAddress, doubling
+// the delay between attempts (capped at ReConnectBackoffThreshold) after each
+// failure. It gives up with ErrReconnectExhausted once the cumulative elapsed
+// time exceeds ReConnectTotalTimeThreshold, or returns ctx's error if ctx is
+// canceled first.
+func (d *Dialer) Dial(ctx context.Context, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	start := time.Now()
+	delay := minReconnectDelay
+
+	for attempt := 1; ; attempt++ {
+		dialCtx, cancel := context.WithTimeout(ctx, d.cfg.ConnectionTimeout)
+		conn, err := grpc.DialContext(dialCtx, d.cfg.GRPCAddress, append([]grpc.DialOption{grpc.WithBlock()}, opts...)...)
+		cancel()
+		if err == nil {
+			return conn, nil
+		}
+
+		elapsed := time.Since(start)
+		logrus.WithError(err).Warnf("trustfabric: dial attempt %d to %s failed after %s", attempt, d.cfg.GRPCAddress, elapsed)
+
+		if elapsed >= d.cfg.ReConnectTotalTimeThreshold {
+			return nil, fmt.Errorf("%w: %s after %d attempts over %s", ErrReconnectExhausted, d.cfg.GRPCAddress, attempt, elapsed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+		if delay > d.cfg.ReConnectBackoffThreshold {
+			delay = d.cfg.ReConnectBackoffThreshold
+		}
+	}
+}
+
+// minReconnectDelay is the delay before the first retry.
+const minReconnectDelay = 50 * time.Millisecond
@@ -0,0 +1,87 @@
+// Copyright 2025 c0r3br34k3r
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trustfabric_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/omega-cyber/mesh-network/internal/config"
+	"github.com/omega-cyber/mesh-network/internal/trustfabric"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// reserveFreeAddr grabs an ephemeral port and immediately frees it, so a
+// Dialer can be pointed at an address nothing is listening on yet.
+func reserveFreeAddr(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	require.NoError(t, l.Close())
+	return addr
+}
+
+func TestDialerSucceedsOnceServerStartsAccepting(t *testing.T) {
+	addr := reserveFreeAddr(t)
+
+	dialer := trustfabric.NewDialer(config.TrustFabricConfig{
+		GRPCAddress:                 addr,
+		ConnectionTimeout:           200 * time.Millisecond,
+		ReConnectBackoffThreshold:   100 * time.Millisecond,
+		ReConnectTotalTimeThreshold: 5 * time.Second,
+	})
+
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			return
+		}
+		grpcServer := grpc.NewServer()
+		defer grpcServer.Stop()
+		grpcServer.Serve(listener)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	conn, err := dialer.Dial(ctx, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+}
+
+func TestDialerReturnsReconnectExhausted(t *testing.T) {
+	addr := reserveFreeAddr(t)
+
+	dialer := trustfabric.NewDialer(config.TrustFabricConfig{
+		GRPCAddress:                 addr,
+		ConnectionTimeout:           100 * time.Millisecond,
+		ReConnectBackoffThreshold:   50 * time.Millisecond,
+		ReConnectTotalTimeThreshold: 300 * time.Millisecond,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := dialer.Dial(ctx, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.Error(t, err)
+	require.True(t, errors.Is(err, trustfabric.ErrReconnectExhausted))
+}